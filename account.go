@@ -0,0 +1,412 @@
+package anticaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// balanceResponse is the body of a getBalance response
+type balanceResponse struct {
+	errorResponse
+	Balance *float64 `json:"balance"`
+}
+
+// balanceGuard fails solves fast while the account balance is below a threshold.
+// The balance is cached for a short window so it is not retrieved before every solve.
+type balanceGuard struct {
+	threshold float64
+
+	mu      sync.Mutex
+	balance float64
+	checked time.Time
+}
+
+// check returns ErrZeroBalance if the balance is below the threshold. If the balance cannot be retrieved,
+// the solve goes ahead and the API reports any account error when the task is created.
+func (g *balanceGuard) check(ctx context.Context, c *Client) error {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if time.Since(g.checked) > balanceCacheTTL {
+		balance, err := c.GetBalance(ctx)
+		if err != nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) {
+				return err
+			}
+			c.logger(ctx).Warn("Could not check the balance before solving", "error", err)
+			return nil
+		}
+		g.balance = balance
+		g.checked = time.Now()
+	}
+
+	if g.balance < g.threshold {
+		return fmt.Errorf("balance %g is below the minimum of %g: %w", g.balance, g.threshold, ErrZeroBalance)
+	}
+
+	return nil
+}
+
+// GetBalance retrieves the current account balance in USD
+func (c *Client) GetBalance(ctx context.Context) (float64, error) {
+	body := clientKeyRequest{
+		ClientKey: c.APIKey,
+	}
+
+	c.logger(ctx).Debug("Retrieving account balance")
+
+	var response balanceResponse
+	err := c.makeRequest(ctx, "/getBalance", body, &response)
+	if err != nil {
+		c.logger(ctx).Error("Failed to get balance", "error", err)
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	// Check for API errors
+	if apiErr := response.apiError(); apiErr != nil {
+		c.logger(ctx).Error("API error getting balance", "error", apiErr)
+		return 0, apiErr
+	}
+
+	if response.Balance == nil {
+		c.logger(ctx).Error("Failed to retrieve balance from response")
+		return 0, errors.New("failed to retrieve balance from response")
+	}
+	balance := *response.Balance
+
+	c.logger(ctx).Info("Account balance retrieved", "balance", balance)
+
+	return balance, nil
+}
+
+// HasSufficientBalance reports whether the account balance is at least the given threshold
+func (c *Client) HasSufficientBalance(ctx context.Context, threshold float64) (bool, error) {
+	balance, err := c.GetBalance(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return balance >= threshold, nil
+}
+
+// Ping checks connectivity and the validity of the API key with a lightweight getBalance call.
+// A rejected key returns an error matching ErrInvalidKey; network failures return a non-API error.
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.GetBalance(ctx); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+
+	return nil
+}
+
+// QueueStats represents the load statistics of an AntiCaptcha queue
+type QueueStats struct {
+	Waiting int     // Number of idle workers online, waiting for a task
+	Load    float64 // Queue load in percents
+	Bid     float64 // Average task solution cost in USD
+	Speed   float64 // Average task solution time in seconds
+	Total   int     // Total number of workers
+}
+
+// queueStatsRequest is the body of a getQueueStats request
+type queueStatsRequest struct {
+	QueueID int `json:"queueId"`
+}
+
+// queueStatsResponse is the body of a getQueueStats response. Numbers are sent as strings by some queues.
+type queueStatsResponse struct {
+	errorResponse
+	Waiting json.Number `json:"waiting"`
+	Load    json.Number `json:"load"`
+	Bid     json.Number `json:"bid"`
+	Speed   json.Number `json:"speed"`
+	Total   json.Number `json:"total"`
+}
+
+// Queue IDs accepted by GetQueueStats and GetSpendingStats, as documented by the API
+const (
+	QueueImageToTextEnglish             = 1  // ImageToText, English language
+	QueueImageToTextRussian             = 2  // ImageToText, Russian language
+	QueueRecaptchaV2                    = 5  // reCAPTCHA v2 with proxy
+	QueueRecaptchaV2Proxyless           = 6  // reCAPTCHA v2 proxyless
+	QueueFunCaptcha                     = 7  // FunCaptcha with proxy
+	QueueFunCaptchaProxyless            = 10 // FunCaptcha proxyless
+	QueueRecaptchaV3Score03             = 18 // reCAPTCHA v3, minScore 0.3
+	QueueRecaptchaV3Score07             = 19 // reCAPTCHA v3, minScore 0.7
+	QueueRecaptchaV3Score09             = 20 // reCAPTCHA v3, minScore 0.9
+	QueueHCaptcha                       = 21 // HCaptcha with proxy
+	QueueHCaptchaProxyless              = 22 // HCaptcha proxyless
+	QueueRecaptchaV2Enterprise          = 23 // reCAPTCHA v2 Enterprise with proxy
+	QueueRecaptchaV2EnterpriseProxyless = 24 // reCAPTCHA v2 Enterprise proxyless
+	QueueAntiGate                       = 25 // AntiGate
+	QueueTurnstile                      = 26 // Turnstile with proxy
+	QueueTurnstileProxyless             = 27 // Turnstile proxyless
+
+	// QueueImageToText is the default ImageToText queue
+	QueueImageToText = QueueImageToTextEnglish
+)
+
+// GetQueueStats retrieves the load statistics of the given queue, one of the Queue* constants
+func (c *Client) GetQueueStats(ctx context.Context, queueID int) (*QueueStats, error) {
+	body := queueStatsRequest{
+		QueueID: queueID,
+	}
+
+	c.logger(ctx).Debug("Retrieving queue stats", "queue_id", queueID)
+
+	var response queueStatsResponse
+	err := c.makeRequest(ctx, "/getQueueStats", body, &response)
+	if err != nil {
+		c.logger(ctx).Error("Failed to get queue stats", "queue_id", queueID, "error", err)
+		return nil, fmt.Errorf("failed to get queue stats: %w", err)
+	}
+
+	// Check for API errors
+	if apiErr := response.apiError(); apiErr != nil {
+		c.logger(ctx).Error("API error getting queue stats", "queue_id", queueID, "error", apiErr)
+		return nil, apiErr
+	}
+
+	stats := &QueueStats{
+		Waiting: int(numberFloat(response.Waiting)),
+		Load:    numberFloat(response.Load),
+		Bid:     numberFloat(response.Bid),
+		Speed:   numberFloat(response.Speed),
+		Total:   int(numberFloat(response.Total)),
+	}
+
+	return stats, nil
+}
+
+// numberFloat converts a number of a response to float64, or 0 if it's missing
+func numberFloat(n json.Number) float64 {
+	f, _ := n.Float64()
+	return f
+}
+
+// spendingStatsQueues maps queue IDs to the queue names accepted by getSpendingStats
+var spendingStatsQueues = map[int]string{
+	QueueImageToTextEnglish:   "English ImageToText",
+	QueueImageToTextRussian:   "Russian ImageToText",
+	QueueRecaptchaV2:          "Recaptcha Proxyon",
+	QueueRecaptchaV2Proxyless: "Recaptcha Proxyless",
+	QueueFunCaptcha:           "Funcaptcha",
+	QueueFunCaptchaProxyless:  "Funcaptcha Proxyless",
+	QueueHCaptcha:             "hCaptcha Proxyon",
+	QueueHCaptchaProxyless:    "hCaptcha Proxyless",
+}
+
+// SpendingPeriod represents the spending of a single hour
+type SpendingPeriod struct {
+	DateFrom time.Time
+	DateTill time.Time
+	Volume   int     // Number of solved tasks
+	Money    float64 // Money spent in USD
+}
+
+// SpendingStats represents the spending of a queue over a date range
+type SpendingStats struct {
+	QueueID     int
+	From        time.Time
+	To          time.Time
+	Periods     []SpendingPeriod
+	TotalVolume int
+	TotalMoney  float64
+}
+
+// spendingStatsRequest is the body of a getSpendingStats request
+type spendingStatsRequest struct {
+	ClientKey string `json:"clientKey"`
+	Date      int64  `json:"date"`
+	Queue     string `json:"queue,omitempty"`
+}
+
+// spendingStatsResponse is the body of a getSpendingStats response
+type spendingStatsResponse struct {
+	errorResponse
+	Data []struct {
+		DateFrom json.Number `json:"dateFrom"`
+		DateTill json.Number `json:"dateTill"`
+		Volume   json.Number `json:"volume"`
+		Money    json.Number `json:"money"`
+	} `json:"data"`
+}
+
+// GetSpendingStats retrieves the per-hour spending between from and to for the given queue.
+// A queue ID of 0 returns the spending of all queues; otherwise only the ImageToText, reCAPTCHA v2,
+// FunCaptcha and HCaptcha Queue* constants are supported. If from and to are zero, the last 24 hours are used.
+// If from equals to, the periods containing that instant are returned.
+func (c *Client) GetSpendingStats(ctx context.Context, from, to time.Time, queueID int) (*SpendingStats, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-24 * time.Hour)
+	}
+	if from.After(to) {
+		return nil, errors.New("from must not be after to")
+	}
+
+	body := spendingStatsRequest{
+		ClientKey: c.APIKey,
+	}
+	if queueID != 0 {
+		queue, ok := spendingStatsQueues[queueID]
+		if !ok {
+			return nil, fmt.Errorf("unsupported queue ID %d for spending stats", queueID)
+		}
+		body.Queue = queue
+	}
+
+	c.logger(ctx).Debug("Retrieving spending stats", "queue_id", queueID, "from", from.Format(time.RFC3339), "to", to.Format(time.RFC3339))
+
+	stats := &SpendingStats{QueueID: queueID, From: from, To: to}
+
+	// The API returns at most 24 hours of stats ending at the given date, so walk the range backwards.
+	// It is queried at least once, so that a range shorter than a day, or a single instant, is still covered.
+	for date := to; ; date = date.Add(-24 * time.Hour) {
+		body.Date = date.Unix()
+
+		var response spendingStatsResponse
+		err := c.makeRequest(ctx, "/getSpendingStats", body, &response)
+		if err != nil {
+			c.logger(ctx).Error("Failed to get spending stats", "queue_id", queueID, "error", err)
+			return nil, fmt.Errorf("failed to get spending stats: %w", err)
+		}
+
+		// Check for API errors
+		if apiErr := response.apiError(); apiErr != nil {
+			c.logger(ctx).Error("API error getting spending stats", "queue_id", queueID, "error", apiErr)
+			return nil, apiErr
+		}
+
+		for _, entry := range response.Data {
+			period := SpendingPeriod{
+				DateFrom: time.Unix(int64(numberFloat(entry.DateFrom)), 0),
+				DateTill: time.Unix(int64(numberFloat(entry.DateTill)), 0),
+				Volume:   int(numberFloat(entry.Volume)),
+				Money:    numberFloat(entry.Money),
+			}
+			if period.DateTill.Before(from) || period.DateFrom.After(to) {
+				continue
+			}
+
+			stats.Periods = append(stats.Periods, period)
+			stats.TotalVolume += period.Volume
+			stats.TotalMoney += period.Money
+		}
+
+		if !date.Add(-24 * time.Hour).After(from) {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+// appStatsModes lists the display modes accepted by getAppStats
+var appStatsModes = map[string]bool{
+	"errors":    true,
+	"views":     true,
+	"downloads": true,
+	"users":     true,
+	"money":     true,
+}
+
+// AppStatsPoint is a single value of an app stats series
+type AppStatsPoint struct {
+	Date  string // Date of the value, as returned by the API
+	Value float64
+}
+
+// AppStatsSeries is a named series of app stats, e.g. the money earned or the tasks solved
+type AppStatsSeries struct {
+	Name   string
+	Points []AppStatsPoint
+}
+
+// AppStats represents the usage stats of an app registered with a soft ID
+type AppStats struct {
+	SoftID   int
+	Mode     string
+	FromDate string
+	ToDate   string
+	Series   []AppStatsSeries
+}
+
+// appStatsRequest is the body of a getAppStats request
+type appStatsRequest struct {
+	ClientKey string `json:"clientKey"`
+	SoftID    int    `json:"softId"`
+	Mode      string `json:"mode,omitempty"`
+}
+
+// appStatsResponse is the body of a getAppStats response
+type appStatsResponse struct {
+	errorResponse
+	FromDate  string             `json:"fromDate"`
+	ToDate    string             `json:"toDate"`
+	ChartData []appStatsChartRow `json:"chartData"`
+}
+
+// appStatsChartRow is a series of a getAppStats response
+type appStatsChartRow struct {
+	Name string `json:"name"`
+	Data []struct {
+		Date  string      `json:"date"`
+		Value json.Number `json:"value"` // Sent as a number or a string depending on the mode
+	} `json:"data"`
+}
+
+// GetAppStats retrieves the usage stats of the app registered with the given soft ID.
+// The mode selects the series returned: "errors" (the default when empty), "views", "downloads", "users" or "money".
+func (c *Client) GetAppStats(ctx context.Context, softID int, mode string) (*AppStats, error) {
+	if softID <= 0 {
+		return nil, errors.New("softID is required")
+	}
+	if mode != "" && !appStatsModes[mode] {
+		return nil, fmt.Errorf("unsupported mode %q: must be errors, views, downloads, users or money", mode)
+	}
+
+	body := appStatsRequest{
+		ClientKey: c.APIKey,
+		SoftID:    softID,
+		Mode:      mode,
+	}
+
+	c.logger(ctx).Debug("Retrieving app stats", "soft_id", softID, "mode", mode)
+
+	var response appStatsResponse
+	err := c.makeRequest(ctx, "/getAppStats", body, &response)
+	if err != nil {
+		c.logger(ctx).Error("Failed to get app stats", "soft_id", softID, "error", err)
+		return nil, fmt.Errorf("failed to get app stats: %w", err)
+	}
+
+	// Check for API errors
+	if apiErr := response.apiError(); apiErr != nil {
+		c.logger(ctx).Error("API error getting app stats", "soft_id", softID, "error", apiErr)
+		return nil, apiErr
+	}
+
+	stats := &AppStats{SoftID: softID, Mode: mode, FromDate: response.FromDate, ToDate: response.ToDate}
+	for _, row := range response.ChartData {
+		series := AppStatsSeries{Name: row.Name}
+		for _, point := range row.Data {
+			series.Points = append(series.Points, AppStatsPoint{Date: point.Date, Value: numberFloat(point.Value)})
+		}
+
+		stats.Series = append(stats.Series, series)
+	}
+
+	return stats, nil
+}
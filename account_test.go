@@ -0,0 +1,146 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client sending its requests to a test server answering every request with body
+func newTestClient(t *testing.T, body string) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+	)
+}
+
+// TestGetAppStats checks that app stats values are decoded whether sent as numbers or strings
+func TestGetAppStats(t *testing.T) {
+	client := newTestClient(t, `{"errorId":0,"fromDate":"2024-01-01","toDate":"2024-01-02","chartData":[
+		{"name":"Money","data":[{"date":"2024-01-01","value":1.5},{"date":"2024-01-02","value":"2.25"}]}]}`)
+
+	stats, err := client.GetAppStats(context.Background(), 1, "money")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.FromDate != "2024-01-01" || stats.ToDate != "2024-01-02" {
+		t.Errorf("unexpected date range %s to %s", stats.FromDate, stats.ToDate)
+	}
+	if len(stats.Series) != 1 || stats.Series[0].Name != "Money" || len(stats.Series[0].Points) != 2 {
+		t.Fatalf("unexpected series: %+v", stats.Series)
+	}
+	if p := stats.Series[0].Points; p[0].Value != 1.5 || p[1].Value != 2.25 {
+		t.Errorf("expected values 1.5 and 2.25, got %v and %v", p[0].Value, p[1].Value)
+	}
+}
+
+// TestGetAppStatsAPIError checks that an error reported by the API is returned as *APIError
+func TestGetAppStatsAPIError(t *testing.T) {
+	client := newTestClient(t, `{"errorId":1,"errorCode":"ERROR_KEY_DOES_NOT_EXIST","errorDescription":"Account authorization key not found"}`)
+
+	_, err := client.GetAppStats(context.Background(), 1, "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode != "ERROR_KEY_DOES_NOT_EXIST" {
+		t.Fatalf("expected an *APIError with code ERROR_KEY_DOES_NOT_EXIST, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected the error to match ErrInvalidKey")
+	}
+}
+
+// TestGetQueueStats checks that queue stats are decoded whether sent as numbers or strings
+func TestGetQueueStats(t *testing.T) {
+	client := newTestClient(t, `{"errorId":0,"waiting":10,"load":"45.5","bid":"0.0005","speed":7.5,"total":"120"}`)
+
+	stats, err := client.GetQueueStats(context.Background(), QueueImageToText)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := QueueStats{Waiting: 10, Load: 45.5, Bid: 0.0005, Speed: 7.5, Total: 120}
+	if *stats != want {
+		t.Errorf("expected %+v, got %+v", want, *stats)
+	}
+}
+
+// TestGetSpendingStats checks that spending periods are decoded and summed
+func TestGetSpendingStats(t *testing.T) {
+	client := newTestClient(t, `{"errorId":0,"data":[
+		{"dateFrom":1704067200,"dateTill":1704070799,"volume":3,"money":0.006},
+		{"dateFrom":"1704070800","dateTill":"1704074399","volume":"2","money":"0.004"}]}`)
+
+	from, to := time.Unix(1704067200, 0), time.Unix(1704074399, 0)
+	stats, err := client.GetSpendingStats(context.Background(), from, to, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats.Periods) != 2 || stats.TotalVolume != 5 || math.Abs(stats.TotalMoney-0.01) > 1e-9 {
+		t.Errorf("expected 2 periods, 5 tasks and 0.01 spent, got %d periods, %d tasks and %v spent", len(stats.Periods), stats.TotalVolume, stats.TotalMoney)
+	}
+	if !stats.Periods[1].DateFrom.Equal(time.Unix(1704070800, 0)) {
+		t.Errorf("unexpected start of the second period: %v", stats.Periods[1].DateFrom)
+	}
+}
+
+// TestGetSpendingStatsShortRange checks that a range shorter than a day, or a single instant, is queried once
+func TestGetSpendingStatsShortRange(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"errorId":0,"data":[
+			{"dateFrom":1704067200,"dateTill":1704070799,"volume":3,"money":0.006},
+			{"dateFrom":1704070800,"dateTill":1704074399,"volume":2,"money":0.004}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+	)
+
+	for _, tc := range []struct {
+		name     string
+		from, to time.Time
+		periods  int
+	}{
+		{"equal", time.Unix(1704068000, 0), time.Unix(1704068000, 0), 1},
+		{"sub-24h", time.Unix(1704068000, 0), time.Unix(1704072000, 0), 2},
+	} {
+		requests = 0
+		stats, err := client.GetSpendingStats(context.Background(), tc.from, tc.to, 0)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if requests != 1 {
+			t.Errorf("%s: expected 1 request, got %d", tc.name, requests)
+		}
+		if len(stats.Periods) != tc.periods {
+			t.Errorf("%s: expected %d periods, got %d", tc.name, tc.periods, len(stats.Periods))
+		}
+	}
+}
+
+// TestGetQueueStatsAPIError checks that an error reported by the API is returned as *APIError
+func TestGetQueueStatsAPIError(t *testing.T) {
+	client := newTestClient(t, `{"errorId":1,"errorCode":"ERROR_KEY_DOES_NOT_EXIST"}`)
+
+	if _, err := client.GetQueueStats(context.Background(), QueueImageToText); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected an error matching ErrInvalidKey, got %v", err)
+	}
+	if _, err := client.GetSpendingStats(context.Background(), time.Time{}, time.Time{}, 0); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected an error matching ErrInvalidKey, got %v", err)
+	}
+}
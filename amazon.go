@@ -0,0 +1,313 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// AmazonWAFSolution holds the values returned for an AWS WAF captcha task
+type AmazonWAFSolution struct {
+	CaptchaVoucher string
+	ExistingToken  string // Empty if the API did not return one
+
+	Raw map[string]interface{} // The full solution object as received, including fields not modeled above
+}
+
+// AmazonWAFProxyless represents the configuration for an AWS WAF captcha proxyless task
+type AmazonWAFProxyless struct {
+	Client          *Client
+	WebsiteURL      string
+	WebsiteKey      string
+	IV              string
+	Context         string
+	ChallengeScript string
+	CaptchaScript   string
+	PollInterval    time.Duration
+	SoftID          int
+}
+
+// amazonTask is the payload of an AWS WAF captcha task
+type amazonTask struct {
+	Type            string `json:"type"`
+	WebsiteURL      string `json:"websiteURL"`
+	WebsiteKey      string `json:"websiteKey"`
+	IV              string `json:"iv"`
+	Context         string `json:"context"`
+	ChallengeScript string `json:"challengeScript,omitempty"`
+	CaptchaScript   string `json:"captchaScript,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t amazonTask) taskType() string {
+	return t.Type
+}
+
+// NewAmazonWAFProxyless creates a new AmazonWAFProxyless task configuration
+func NewAmazonWAFProxyless(client *Client) *AmazonWAFProxyless {
+	return &AmazonWAFProxyless{
+		Client: client,
+		SoftID: 0,
+	}
+}
+
+// SetWebsiteURL sets the website URL for the AWS WAF task
+func (a *AmazonWAFProxyless) SetWebsiteURL(url string) {
+	a.WebsiteURL = url
+}
+
+// SetWebsiteKey sets the "key" value of the window.gokuProps object of the page
+func (a *AmazonWAFProxyless) SetWebsiteKey(key string) {
+	a.WebsiteKey = key
+}
+
+// SetIV sets the "iv" value of the window.gokuProps object of the page
+func (a *AmazonWAFProxyless) SetIV(iv string) {
+	a.IV = iv
+}
+
+// SetContext sets the "context" value of the window.gokuProps object of the page
+func (a *AmazonWAFProxyless) SetContext(value string) {
+	a.Context = value
+}
+
+// SetChallengeScript sets the URL of the challenge.js script of the page
+func (a *AmazonWAFProxyless) SetChallengeScript(url string) {
+	a.ChallengeScript = url
+}
+
+// SetCaptchaScript sets the URL of the captcha.js script of the page
+func (a *AmazonWAFProxyless) SetCaptchaScript(url string) {
+	a.CaptchaScript = url
+}
+
+// SetSoftID sets the soft ID for the AWS WAF task
+func (a *AmazonWAFProxyless) SetSoftID(softID int) {
+	a.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (a *AmazonWAFProxyless) SetPollInterval(interval time.Duration) {
+	a.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (a *AmazonWAFProxyless) SolveAndReturnSolution() (AmazonWAFSolution, float64, error) {
+	return a.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the captcha voucher.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (a *AmazonWAFProxyless) SolveAndReturnSolutionContext(ctx context.Context) (AmazonWAFSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return AmazonWAFSolution{}, result.id(), err
+	}
+
+	solution, err := a.solution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// Solve implements Solver, with the captcha voucher as the token
+func (a *AmazonWAFProxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := a.solution(ctx, result)
+	return newSolution(result, solution.CaptchaVoucher), err
+}
+
+// solution extracts the AWS WAF solution from a task result
+func (a *AmazonWAFProxyless) solution(ctx context.Context, result *TaskResult) (AmazonWAFSolution, error) {
+	voucher, ok := result.Solution["captcha_voucher"].(string)
+	if !ok {
+		a.Client.logger(ctx).Error("captcha_voucher not found in solution", "task_id", result.TaskID)
+		return AmazonWAFSolution{}, errors.New("captcha_voucher not found in solution")
+	}
+
+	existingToken, _ := result.Solution["existing_token"].(string)
+
+	a.Client.logger(ctx).Info("AWS WAF captcha solved", "task_id", result.TaskID, "captcha_voucher", a.Client.redact(voucher))
+	return AmazonWAFSolution{CaptchaVoucher: voucher, ExistingToken: existingToken, Raw: result.Solution}, nil
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (a *AmazonWAFProxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return a.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (a *AmazonWAFProxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := a.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Client.logger(ctx).Debug("Creating AWS WAF proxyless task")
+
+	return a.Client.solveTask(ctx, task, a.SoftID, a.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (a *AmazonWAFProxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := a.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	a.Client.logger(ctx).Debug("Submitting AWS WAF proxyless task")
+
+	return a.Client.createTaskAsync(ctx, task, a.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (a *AmazonWAFProxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a.task(), nil
+}
+
+// task builds the AWS WAF proxyless task
+func (a *AmazonWAFProxyless) task() amazonTask {
+	return amazonTask{
+		Type:            "AmazonTaskProxyless",
+		WebsiteURL:      a.WebsiteURL,
+		WebsiteKey:      a.WebsiteKey,
+		IV:              a.IV,
+		Context:         a.Context,
+		ChallengeScript: a.ChallengeScript,
+		CaptchaScript:   a.CaptchaScript,
+	}
+}
+
+// Validate checks that the required fields are set, without sending anything
+func (a *AmazonWAFProxyless) Validate() error {
+	if err := validateWebsiteURL(a.WebsiteURL); err != nil {
+		return err
+	}
+	if a.WebsiteKey == "" {
+		return missingField("websiteKey")
+	}
+	if a.IV == "" {
+		return missingField("iv")
+	}
+	if a.Context == "" {
+		return missingField("context")
+	}
+
+	return nil
+}
+
+// AmazonWAFTask represents the configuration for an AWS WAF captcha task solved through a proxy
+type AmazonWAFTask struct {
+	AmazonWAFProxyless
+	Proxy Proxy
+}
+
+// NewAmazonWAFTask creates a new AmazonWAFTask configuration
+func NewAmazonWAFTask(client *Client) *AmazonWAFTask {
+	return &AmazonWAFTask{AmazonWAFProxyless: *NewAmazonWAFProxyless(client)}
+}
+
+// SetProxy sets the proxy the worker solves the captcha through
+func (a *AmazonWAFTask) SetProxy(proxy Proxy) {
+	a.Proxy = proxy
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (a *AmazonWAFTask) SolveAndReturnSolution() (AmazonWAFSolution, float64, error) {
+	return a.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the captcha voucher.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (a *AmazonWAFTask) SolveAndReturnSolutionContext(ctx context.Context) (AmazonWAFSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return AmazonWAFSolution{}, result.id(), err
+	}
+
+	solution, err := a.solution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// Solve implements Solver, with the captcha voucher as the token
+func (a *AmazonWAFTask) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := a.solution(ctx, result)
+	return newSolution(result, solution.CaptchaVoucher), err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (a *AmazonWAFTask) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return a.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (a *AmazonWAFTask) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := a.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Client.logger(ctx).Debug("Creating AWS WAF task")
+
+	return a.Client.solveTask(ctx, task, a.SoftID, a.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (a *AmazonWAFTask) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := a.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	a.Client.logger(ctx).Debug("Submitting AWS WAF task")
+
+	return a.Client.createTaskAsync(ctx, task, a.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (a *AmazonWAFTask) payload(ctx context.Context) (taskPayload, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := a.AmazonWAFProxyless.task()
+	task.Type = "AmazonTask"
+	proxy, err := a.Client.drawProxy(ctx, a.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	task.proxyPayload = proxy
+
+	return task, nil
+}
+
+// Validate checks that the required fields and the proxy are set, without sending anything
+func (a *AmazonWAFTask) Validate() error {
+	if err := a.AmazonWAFProxyless.Validate(); err != nil {
+		return err
+	}
+
+	return a.Client.validateProxy(a.Proxy)
+}
@@ -0,0 +1,255 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AntiBotCookieSolution holds the cookies a worker obtained by passing an anti-bot screen, e.g. DataDome,
+// with the browser values they are bound to
+type AntiBotCookieSolution struct {
+	Cookies      map[string]string // Cookies set by the website, by name
+	Cookie       string            // The same cookies as a "name1=value1; name2=value2" Cookie header value
+	UserAgent    string            // User agent of the worker browser, which must be sent along with the cookies
+	LocalStorage map[string]string // Local storage of the website, empty if the API did not return it
+	URL          string            // URL the worker browser ended at
+	Headers      http.Header       // Headers the cookies must be sent with, including Cookie and User-Agent
+
+	Raw map[string]interface{} // The full solution object as received, including fields not modeled above
+}
+
+// Header returns a copy of the request headers the cookies must be sent with
+func (s AntiBotCookieSolution) Header() http.Header {
+	if s.Headers == nil {
+		return make(http.Header)
+	}
+
+	return s.Headers.Clone()
+}
+
+// Apply sets the headers of the solution on a request to the protected website, replacing any previous values
+func (s AntiBotCookieSolution) Apply(req *http.Request) {
+	for name, values := range s.Header() {
+		req.Header[name] = values
+	}
+}
+
+// AntiBotCookie represents the configuration for a task passing an anti-bot screen and returning its cookies.
+// It is always solved through a proxy, which must also send the requests the cookies are used for.
+type AntiBotCookie struct {
+	Client       *Client
+	WebsiteURL   string
+	Proxy        Proxy
+	PollInterval time.Duration
+	SoftID       int
+}
+
+// antiBotCookieTask is the payload of an anti-bot cookie task
+type antiBotCookieTask struct {
+	Type       string `json:"type"`
+	WebsiteURL string `json:"websiteURL"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t antiBotCookieTask) taskType() string {
+	return t.Type
+}
+
+// NewAntiBotCookie creates a new AntiBotCookie task configuration
+func NewAntiBotCookie(client *Client) *AntiBotCookie {
+	return &AntiBotCookie{
+		Client: client,
+		SoftID: 0,
+	}
+}
+
+// SetWebsiteURL sets the URL of the page protected by the anti-bot screen
+func (a *AntiBotCookie) SetWebsiteURL(url string) {
+	a.WebsiteURL = url
+}
+
+// SetProxy sets the proxy the worker passes the anti-bot screen through
+func (a *AntiBotCookie) SetProxy(proxy Proxy) {
+	a.Proxy = proxy
+}
+
+// SetSoftID sets the soft ID for the anti-bot cookie task
+func (a *AntiBotCookie) SetSoftID(softID int) {
+	a.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (a *AntiBotCookie) SetPollInterval(interval time.Duration) {
+	a.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (a *AntiBotCookie) SolveAndReturnSolution() (AntiBotCookieSolution, float64, error) {
+	return a.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the cookies with their user agent.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (a *AntiBotCookie) SolveAndReturnSolutionContext(ctx context.Context) (AntiBotCookieSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return AntiBotCookieSolution{}, result.id(), err
+	}
+
+	solution, err := a.solution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// Solve implements Solver, with the Cookie header value as the token
+func (a *AntiBotCookie) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := a.solution(ctx, result)
+	return newSolution(result, solution.Cookie), err
+}
+
+// solution extracts the cookies and browser values from a task result
+func (a *AntiBotCookie) solution(ctx context.Context, result *TaskResult) (AntiBotCookieSolution, error) {
+	cookies, ok := result.Solution["cookies"].(map[string]interface{})
+	if !ok || len(cookies) == 0 {
+		a.Client.logger(ctx).Error("cookies not found in solution", "task_id", result.TaskID)
+		return AntiBotCookieSolution{}, errors.New("cookies not found in solution")
+	}
+
+	solution := AntiBotCookieSolution{
+		Cookies:      stringMap(cookies),
+		LocalStorage: map[string]string{},
+		Raw:          result.Solution,
+	}
+	solution.Cookie = formatCookies(solution.Cookies)
+	solution.URL, _ = result.Solution["url"].(string)
+	if localStorage, ok := result.Solution["localStorage"].(map[string]interface{}); ok {
+		solution.LocalStorage = stringMap(localStorage)
+	}
+	if fingerprint, ok := result.Solution["fingerprint"].(map[string]interface{}); ok {
+		solution.UserAgent, _ = fingerprint["self.navigator.userAgent"].(string)
+	}
+	solution.Headers = solutionHeaders(result.Solution, solution.Cookie, solution.UserAgent)
+
+	a.Client.logger(ctx).Info("Anti-bot cookies obtained", "task_id", result.TaskID, "cookies", len(solution.Cookies))
+	return solution, nil
+}
+
+// solutionHeaders builds the headers the cookies must be sent with from the headers the worker sent, if the API
+// returned them, with Cookie and User-Agent taken from the solved cookies and the worker fingerprint
+func solutionHeaders(solution map[string]interface{}, cookie, userAgent string) http.Header {
+	header := make(http.Header)
+	switch headers := solution["headers"].(type) {
+	case map[string]interface{}:
+		for name, value := range headers {
+			header.Set(name, fmt.Sprint(value))
+		}
+	case []interface{}:
+		// "Name: value" lines, as sent by the worker browser
+		for _, line := range headers {
+			if s, ok := line.(string); ok {
+				if name, value, found := strings.Cut(s, ":"); found {
+					header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+				}
+			}
+		}
+	}
+	// Set by the HTTP client for each request
+	for _, name := range []string{"Host", "Content-Length", "Connection"} {
+		header.Del(name)
+	}
+
+	if cookie != "" {
+		header.Set("Cookie", cookie)
+	}
+	if userAgent != "" {
+		header.Set("User-Agent", userAgent)
+	}
+
+	return header
+}
+
+// stringMap converts the values of a decoded JSON object to strings
+func stringMap(m map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(m))
+	for key, value := range m {
+		if s, ok := value.(string); ok {
+			out[key] = s
+		} else {
+			out[key] = fmt.Sprint(value)
+		}
+	}
+
+	return out
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (a *AntiBotCookie) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return a.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (a *AntiBotCookie) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := a.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Client.logger(ctx).Debug("Creating anti-bot cookie task")
+
+	return a.Client.solveTask(ctx, task, a.SoftID, a.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (a *AntiBotCookie) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := a.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	a.Client.logger(ctx).Debug("Submitting anti-bot cookie task")
+
+	return a.Client.createTaskAsync(ctx, task, a.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (a *AntiBotCookie) payload(ctx context.Context) (taskPayload, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	proxy, err := a.Client.drawProxy(ctx, a.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	task := antiBotCookieTask{
+		Type:         "AntiBotCookieTask",
+		WebsiteURL:   a.WebsiteURL,
+		proxyPayload: proxy,
+	}
+
+	return task, nil
+}
+
+// Validate checks that the website URL and the proxy are set, without sending anything
+func (a *AntiBotCookie) Validate() error {
+	if err := validateWebsiteURL(a.WebsiteURL); err != nil {
+		return err
+	}
+
+	return a.Client.validateProxy(a.Proxy)
+}
@@ -0,0 +1,44 @@
+package anticaptcha
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// TestAntiBotCookieSolutionHeaders checks that the solution headers merge the worker headers with the solved cookies
+func TestAntiBotCookieSolutionHeaders(t *testing.T) {
+	antiBot := NewAntiBotCookie(NewClientWithOptions("test-key", WithSilentLogging()))
+	result := &TaskResult{TaskID: 7, Solution: map[string]interface{}{
+		"cookies":     map[string]interface{}{"datadome": "abc"},
+		"fingerprint": map[string]interface{}{"self.navigator.userAgent": "Mozilla/5.0 worker"},
+		"headers":     []interface{}{"Accept-Language: en-US,en;q=0.9", "Cookie: stale=1", "Host: website.com"},
+	}}
+
+	solution, err := antiBot.solution(context.Background(), result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := solution.Headers.Get("Accept-Language"); got != "en-US,en;q=0.9" {
+		t.Errorf("expected the worker Accept-Language, got %q", got)
+	}
+	if got := solution.Headers.Get("Cookie"); got != "datadome=abc" {
+		t.Errorf("expected the solved cookies, got %q", got)
+	}
+	if got := solution.Headers.Get("User-Agent"); got != "Mozilla/5.0 worker" {
+		t.Errorf("expected the worker user agent, got %q", got)
+	}
+	if solution.Headers.Get("Host") != "" {
+		t.Error("expected the Host header to be dropped")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://website.com", nil)
+	req.Header.Set("Cookie", "old=1")
+	solution.Apply(req)
+	if got := req.Header.Get("Cookie"); got != "datadome=abc" {
+		t.Errorf("expected Apply to replace the Cookie header, got %q", got)
+	}
+	if got := req.Header.Get("Accept-Language"); got != "en-US,en;q=0.9" {
+		t.Errorf("expected Apply to set Accept-Language, got %q", got)
+	}
+}
@@ -2,204 +2,1105 @@ package anticaptcha
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
 // Constants for the AntiCaptcha API
 const (
-	apiBaseURL     = "https://api.anti-captcha.com"
-	checkInterval  = 2 * time.Second
-	defaultTimeout = 60 * time.Second
+	apiBaseURL        = "https://api.anti-captcha.com"
+	checkInterval     = 2 * time.Second
+	defaultTimeout    = 60 * time.Second // Default duration of a whole solve
+	defaultMaxRetries = 2
+
+	defaultHTTPTimeout = 30 * time.Second // Default duration of a single request
+
+	defaultMaxPollAttempts = 300
+
+	maxErrorBodySize = 512
+
+	defaultNoSlotRetryDelay = 5 * time.Second
+	defaultNoSlotMaxWait    = 30 * time.Second
+
+	balanceCacheTTL = 30 * time.Second
+
+	firstPollJitter = 0.2 // Random spread of the first poll delay, ±20%
+
+	// Connection pooling of the default transport, sized for many concurrent solves against a single host
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 100
+	defaultIdleConnTimeout     = 90 * time.Second
 )
 
 // Default logger for the package
-var defaultLogger = log.New(os.Stdout, "AntiCaptcha: ", log.LstdFlags)
+var defaultLogger = NewStdLogger(log.New(os.Stdout, "AntiCaptcha: ", log.LstdFlags))
+
+// Doer sends HTTP requests. *http.Client satisfies it, and tests can provide their own
+// to assert on the requests sent and return crafted responses.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
 
 // Client represents an AntiCaptcha API client
 type Client struct {
-	APIKey     string
-	HTTPClient *http.Client
-	Logger     *log.Logger
+	APIKey       string
+	HTTPClient   Doer   // *http.Client by default
+	UserAgent    string // User-Agent header of the API requests, not of the captcha tasks; none is set if empty
+	Logger       Logger
+	BaseURL      string
+	PollInterval time.Duration
+	PollBackoff  *Backoff
+	Timeout      time.Duration // Maximum duration of a whole solve, from task creation to the last poll
+	HTTPTimeout  time.Duration // Maximum duration of a single request, retried if transient; no limit if zero
+	SoftID       int
+	CallbackURL  string // URL the results of tasks submitted with Submit or CreateTaskAsync are posted to
+	LanguagePool string // Pool of workers tasks are solved by, e.g. "en" or "rn"; the API default if empty
+	MaxRetries   int
+	RetryBackoff *Backoff
+	Redact       bool         // Mask the API key and solutions in log output
+	Tracer       Tracer       // Traces requests and solves when set
+	Metrics      Metrics      // Observes every solve when set
+	RateLimiter  *RateLimiter // Limits the rate of every request sent by the client when set
+
+	// ProxyProvider supplies a fresh proxy to each proxied task created without a proxy of its own
+	ProxyProvider ProxyProvider
+
+	// Interceptors wrap the sending of every API request, retries included, the first being the outermost
+	Interceptors []Interceptor
+
+	// Progress is called from the poll loop each time a task is still processing.
+	// It runs synchronously, so it must return quickly.
+	Progress func(status string, attempt int)
+
+	MaxPollAttempts  int
+	NoSlotRetryDelay time.Duration
+	NoSlotMaxWait    time.Duration
+
+	// FirstPollDelay is the delay before the first check of a task result, with ±20% jitter.
+	// Zero uses a default per task type, e.g. 1s for images and 5s for HCaptcha; a negative value checks right away.
+	FirstPollDelay time.Duration
+
+	inflight   *inflightGroup // Shares identical concurrent image solves when set
+	imageCache *imageCache    // Caches image solutions when set
+	minBalance *balanceGuard  // Checks the balance before solving when set
+	dryRun     *dryRun        // Simulates solves instead of calling the API when set
+	stats      clientStats    // Cumulative stats of the solves, returned by Stats
+	closed     int32          // Set to 1 by Close
 }
 
 // NewClient creates a new AntiCaptcha API client with a logger.
 // If no logger is provided, it uses the default logger.
-func NewClient(apiKey string, logger *log.Logger) *Client {
-	if logger == nil {
-		logger = defaultLogger
+func NewClient(apiKey string, logger *log.Logger, opts ...Option) *Client {
+	pollBackoff := DefaultPollBackoff
+	c := &Client{
+		APIKey:       apiKey,
+		HTTPClient:   &http.Client{Transport: newTransport()},
+		Logger:       defaultLogger,
+		UserAgent:    defaultUserAgent,
+		BaseURL:      apiBaseURL,
+		PollInterval: checkInterval,
+		PollBackoff:  &pollBackoff,
+		Timeout:      defaultTimeout,
+		HTTPTimeout:  defaultHTTPTimeout,
+		MaxRetries:   defaultMaxRetries,
+		Redact:       true,
+
+		MaxPollAttempts:  defaultMaxPollAttempts,
+		NoSlotRetryDelay: defaultNoSlotRetryDelay,
+		NoSlotMaxWait:    defaultNoSlotMaxWait,
+	}
+
+	if logger != nil {
+		c.Logger = NewStdLogger(logger)
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// newTransport returns the default transport of the client: http.DefaultTransport with
+// a connection pool keeping enough idle connections to the API for concurrent solves
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = defaultMaxIdleConns
+	transport.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = defaultIdleConnTimeout
+
+	return transport
+}
+
+// Close releases the resources held by the client: the idle connections of its HTTP client and the cached
+// image solutions. The client is unusable afterwards, and its requests fail with ErrClientClosed.
+// Closing a client twice has no effect.
+func (c *Client) Close() error {
+	if !atomic.CompareAndSwapInt32(&c.closed, 0, 1) {
+		return nil
+	}
+
+	if closer, ok := c.HTTPClient.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+	c.ClearImageCache()
+
+	return nil
+}
+
+// NewClientWithOptions creates a new AntiCaptcha API client configured by the given options.
+// Unless WithLogger is given, it uses the default logger.
+func NewClientWithOptions(apiKey string, opts ...Option) *Client {
+	return NewClient(apiKey, nil, opts...)
+}
+
+// Environment variables read by NewClientFromEnv
+const (
+	EnvAPIKey       = "ANTICAPTCHA_API_KEY"
+	EnvBaseURL      = "ANTICAPTCHA_BASE_URL"
+	EnvTimeout      = "ANTICAPTCHA_TIMEOUT"
+	EnvPollInterval = "ANTICAPTCHA_POLL_INTERVAL"
+	EnvSoftID       = "ANTICAPTCHA_SOFT_ID"
+)
+
+// NewClientFromEnv creates a new AntiCaptcha API client with the API key read from ANTICAPTCHA_API_KEY.
+// ANTICAPTCHA_BASE_URL, ANTICAPTCHA_TIMEOUT and ANTICAPTCHA_POLL_INTERVAL (durations such as "90s")
+// and ANTICAPTCHA_SOFT_ID are also read when set; the given options are applied after them, so they take precedence.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	apiKey := os.Getenv(EnvAPIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", EnvAPIKey)
 	}
 
-	return &Client{
-		APIKey:     apiKey,
-		HTTPClient: &http.Client{Timeout: defaultTimeout},
-		Logger:     logger,
+	var envOpts []Option
+	if baseURL := os.Getenv(EnvBaseURL); baseURL != "" {
+		if err := validateBaseURL(baseURL); err != nil {
+			return nil, fmt.Errorf("invalid environment variable %s: %w", EnvBaseURL, err)
+		}
+		envOpts = append(envOpts, WithBaseURL(baseURL))
+	}
+	if value := os.Getenv(EnvTimeout); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment variable %s: %w", EnvTimeout, err)
+		}
+		envOpts = append(envOpts, WithTimeout(timeout))
+	}
+	if value := os.Getenv(EnvPollInterval); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment variable %s: %w", EnvPollInterval, err)
+		}
+		envOpts = append(envOpts, WithPollInterval(interval))
 	}
+	if value := os.Getenv(EnvSoftID); value != "" {
+		softID, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment variable %s: %w", EnvSoftID, err)
+		}
+		envOpts = append(envOpts, WithSoftID(softID))
+	}
+
+	return NewClientWithOptions(apiKey, append(envOpts, opts...)...), nil
 }
 
-// makeRequest sends a request to the AntiCaptcha API and decodes the response
-func (c *Client) makeRequest(ctx context.Context, endpoint string, body interface{}, response interface{}) error {
+// baseURL returns the API base URL, falling back to the default if unset
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return apiBaseURL
+	}
+
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+// validateBaseURL checks that a base URL is an absolute http or https URL
+func validateBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid base URL %q: must be an absolute http or https URL", baseURL)
+	}
+
+	return nil
+}
+
+// validateWebsiteURL checks that the website URL of a task is set and is an absolute http or https URL
+func validateWebsiteURL(websiteURL string) error {
+	if websiteURL == "" {
+		return missingField("websiteURL")
+	}
+
+	u, err := url.ParseRequestURI(websiteURL)
+	if err != nil {
+		return fmt.Errorf("invalid websiteURL %q: %w", websiteURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid websiteURL %q: must be an absolute http or https URL", websiteURL)
+	}
+
+	return nil
+}
+
+// PollConfig describes how the result of a task is polled
+type PollConfig struct {
+	Interval    time.Duration // Interval between checks, 2 seconds if zero; the floor of Backoff when both are set
+	MaxAttempts int           // Maximum number of checks before giving up with ErrSolveTimeout, unlimited if zero
+	Backoff     *Backoff      // Exponential backoff between checks, starting at Interval if its Initial is zero
+	FirstDelay  time.Duration // Delay before the first check, with ±20% jitter; the first check is immediate if zero
+}
+
+// firstPollDelays are the default delays before the first check of a task result, by task type without its
+// "Proxyless" suffix. They are a little below the usual solve time, so the first check rarely finds the task
+// still processing. Other task types are checked right away.
+var firstPollDelays = map[string]time.Duration{
+	"ImageToTextTask":           time.Second,
+	"HCaptchaTask":              5 * time.Second,
+	"RecaptchaV2Task":           5 * time.Second,
+	"RecaptchaV2EnterpriseTask": 5 * time.Second,
+	"RecaptchaV3Task":           3 * time.Second,
+	"TurnstileTask":             3 * time.Second,
+	"FunCaptchaTask":            5 * time.Second,
+	"GeeTestTask":               3 * time.Second,
+	"AmazonTask":                5 * time.Second,
+	"AntiGateTask":              10 * time.Second,
+	"AntiBotCookieTask":         10 * time.Second,
+}
+
+// firstPollDelay returns the delay before the first check of the result of a task of the given type:
+// the client FirstPollDelay if positive, none if negative, and the default of the task type otherwise
+func (c *Client) firstPollDelay(taskType string) time.Duration {
+	switch {
+	case c.FirstPollDelay > 0:
+		return c.FirstPollDelay
+	case c.FirstPollDelay < 0:
+		return 0
+	default:
+		return firstPollDelays[strings.TrimSuffix(taskType, "Proxyless")]
+	}
+}
+
+// delay returns the delay to wait after the given poll attempt, starting at 0.
+// It uses the backoff when one is set, never going below a positive interval, and the fixed interval otherwise.
+func (p PollConfig) delay(attempt int) time.Duration {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = checkInterval
+	}
+	if p.Backoff == nil {
+		return interval
+	}
+
+	delay := p.Backoff.delay(attempt, interval)
+	if delay < p.Interval {
+		delay = p.Interval
+	}
+
+	return delay
+}
+
+// pollConfig returns the poll configuration of the client
+func (c *Client) pollConfig() PollConfig {
+	return PollConfig{
+		Interval:    c.PollInterval,
+		MaxAttempts: c.MaxPollAttempts,
+		Backoff:     c.PollBackoff,
+	}
+}
+
+// taskPollConfig returns the poll configuration of a task of the given type, with its first poll delay.
+// A positive pollInterval replaces the poll interval and backoff of the client.
+func (c *Client) taskPollConfig(taskType string, pollInterval time.Duration) PollConfig {
+	poll := c.pollConfig()
+	if pollInterval > 0 {
+		poll.Interval = pollInterval
+		poll.Backoff = nil
+	}
+	poll.FirstDelay = c.firstPollDelay(taskType)
+
+	return poll
+}
+
+// withTimeout derives a context bounded by the client timeout.
+// A deadline already set on the parent context is kept as is, so it is never shortened.
+func (c *Client) withTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := parent.Deadline(); ok {
+		return context.WithCancel(parent)
+	}
+
+	return context.WithTimeout(parent, c.solveTimeout())
+}
+
+// solveTimeout returns the client timeout of a solve, or the default if unset
+func (c *Client) solveTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return defaultTimeout
+	}
+
+	return c.Timeout
+}
+
+// makeRequest sends a request to the AntiCaptcha API and decodes the response.
+// Transient failures (network errors, 5xx and 429 responses) are retried up to MaxRetries times with backoff,
+// or after the delay given by the Retry-After header when the server sends one.
+// Creating a task is paid and not idempotent, so /createTask is only retried when the request never reached the server
+// or was rejected without being processed (a 429, or a 503 with Retry-After).
+func (c *Client) makeRequest(ctx context.Context, endpoint string, body interface{}, response interface{}) (err error) {
+	ctx, span := c.startSpan(ctx, "anticaptcha "+endpoint)
+	defer func() { endSpan(span, err) }()
+
+	if atomic.LoadInt32(&c.closed) == 1 {
+		return ErrClientClosed
+	}
+
 	// Prepare URL
-	u, err := url.Parse(apiBaseURL + endpoint)
+	if err := validateBaseURL(c.baseURL()); err != nil {
+		c.logger(ctx).Error("Invalid base URL", "error", err)
+		return err
+	}
+	u, err := url.Parse(c.baseURL() + endpoint)
 	if err != nil {
-		c.Logger.Printf("Error parsing URL: %v\n", err)
+		c.logger(ctx).Error("Error parsing URL", "error", err)
 		return fmt.Errorf("failed to parse URL: %w", err)
 	}
 
 	// Marshal the body to JSON
 	b, err := json.Marshal(body)
 	if err != nil {
-		c.Logger.Printf("Error marshaling request body: %v\n", err)
+		c.logger(ctx).Error("Error marshaling request body", "error", err)
 		return fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
+	idempotent := endpoint != "/createTask"
+
+	for attempt := 0; ; attempt++ {
+		// Wait for the shared rate limit budget, retries included
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limit wait aborted: %w", err)
+		}
+
+		retry, err := c.doRequest(ctx, span, u, b, response, idempotent)
+		if err == nil || !retry || attempt >= c.MaxRetries {
+			return err
+		}
+
+		backoff := DefaultRetryBackoff
+		if c.RetryBackoff != nil {
+			backoff = *c.RetryBackoff
+		}
+		delay := backoff.delay(attempt, DefaultRetryBackoff.Initial)
+
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+			delay = httpErr.RetryAfter
+		}
+
+		c.logger(ctx).Warn("Retrying request", "url", u.String(), "delay", delay, "attempt", attempt+1, "max_retries", c.MaxRetries, "error", err)
+		if serr := sleep(ctx, delay); serr != nil {
+			return fmt.Errorf("%w (retry aborted: %w)", err, serr)
+		}
+	}
+}
+
+// doRequest sends a single request to the AntiCaptcha API and decodes the response, bounded by the HTTP timeout.
+// It reports whether the failure is transient and the request may be retried. A request that isn't idempotent
+// may only be retried when it failed before being sent or was rejected, as the server may otherwise have acted on it.
+func (c *Client) doRequest(ctx context.Context, span Span, u *url.URL, b []byte, response interface{}, idempotent bool) (bool, error) {
+	// The request timeout only bounds this attempt; ctx still bounds the whole solve
+	reqCtx := ctx
+	if c.HTTPTimeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.HTTPTimeout)
+		defer cancel()
+	}
+
 	// Create a new HTTP request with context
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewBuffer(b))
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, u.String(), bytes.NewBuffer(b))
 	if err != nil {
-		c.Logger.Printf("Error creating HTTP request: %v\n", err)
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+		c.logger(ctx).Error("Error creating HTTP request", "error", err)
+		return false, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	if id, ok := RequestIDFromContext(ctx); ok {
+		req.Header.Set(requestIDHeader, id)
+	}
+	// Setting the header disables the transparent decompression of the transport, so the body is decompressed below
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	// Log the request being sent
-	c.Logger.Printf("Sending request to %s with body: %v\n", u.String(), len(string(b)))
+	c.logger(ctx).Debug("Sending request", "url", u.String(), "body_size", len(b))
 
 	// Send the request
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
-		c.Logger.Printf("Request failed: %v\n", err)
-		return fmt.Errorf("request failed: %w", err)
+		c.logger(ctx).Error("Request failed", "url", u.String(), "error", err)
+		if !idempotent {
+			return ctx.Err() == nil && isNotSent(err), fmt.Errorf("request failed: %w", err)
+		}
+		return ctx.Err() == nil && isTransient(err), fmt.Errorf("request failed: %w", err)
 	}
+	span.SetAttribute("http.status_code", resp.StatusCode)
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
-			c.Logger.Printf("Error closing response body: %v\n", cerr)
+			c.logger(ctx).Warn("Error closing response body", "error", cerr)
 		}
 	}()
 
-	// Check for non-2xx status codes
+	body, err := decodedBody(resp)
+	if err != nil {
+		c.logger(ctx).Error("Error decompressing response", "url", u.String(), "error", err)
+		return idempotent && ctx.Err() == nil && isTransient(err), fmt.Errorf("failed to decompress response: %w", err)
+	}
+
+	// Check for non-2xx status codes, keeping the start of the body for diagnostics
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		c.Logger.Printf("Received non-2xx status code: %d\n", resp.StatusCode)
-		return fmt.Errorf("non-2xx status code: %d", resp.StatusCode)
+		snippet, _ := io.ReadAll(io.LimitReader(body, maxErrorBodySize))
+		httpErr := &HTTPError{
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(snippet)),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+		c.logger(ctx).Error("Received non-2xx status code", "url", u.String(), "status_code", resp.StatusCode, "body", httpErr.Body)
+		return httpErr.rejected() || (idempotent && resp.StatusCode >= 500), httpErr
 	}
 
-	// Decode the response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		c.Logger.Printf("Error decoding response: %v\n", err)
-		return fmt.Errorf("failed to decode response: %w", err)
+	// Read and decode the response
+	data, err := io.ReadAll(body)
+	if err != nil {
+		c.logger(ctx).Error("Error reading response", "url", u.String(), "error", err)
+		return idempotent && ctx.Err() == nil && isTransient(err), fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(data, response); err != nil {
+		c.logger(ctx).Error("Error decoding response", "url", u.String(), "error", err)
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if c.Tracer != nil {
+		var errResp errorResponse
+		if json.Unmarshal(data, &errResp) == nil {
+			span.SetAttribute("anticaptcha.error_id", errResp.ErrorID)
+		}
 	}
 
 	// Log the received response
-	c.Logger.Printf("Received response: %v\n", response)
+	c.logger(ctx).Debug("Received response", "url", u.String(), "body", c.redactBody(data))
 
-	return nil
+	return false, nil
 }
 
-// createTaskImage creates an image-to-text task on the AntiCaptcha API
-func (c *Client) createTaskImage(ctx context.Context, imgString string) (float64, error) {
-	body := map[string]interface{}{
-		"clientKey": c.APIKey,
-		"task": map[string]interface{}{
-			"type": "ImageToTextTask",
-			"body": imgString,
-		},
+// decodedBody returns the body of a response, decompressed if the server sent it gzip encoded
+func decodedBody(resp *http.Response) (io.Reader, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
 	}
 
-	c.Logger.Println("Creating task for image captcha...")
+	return gzip.NewReader(resp.Body)
+}
+
+// isTransient reports whether a request error is a network failure worth retrying
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isNotSent reports whether a request error happened before the request was sent,
+// such as a refused connection or a failed DNS lookup, so that the server can't have acted on it
+func isNotSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// createTask submits a task to the AntiCaptcha API and returns its ID.
+// If callbackURL is set, the API posts the result there once the task is solved.
+func (c *Client) createTask(ctx context.Context, task interface{}, softID int, callbackURL string) (float64, error) {
+	body := CreateTaskRequest{
+		ClientKey:    c.APIKey,
+		Task:         task,
+		SoftID:       softID,
+		LanguagePool: c.LanguagePool,
+		CallbackURL:  callbackURL,
+	}
+	if softID == 0 {
+		body.SoftID = c.SoftID
+	}
+	if p, ok := task.(languagePooler); ok && p.languagePool() != "" {
+		body.LanguagePool = p.languagePool()
+	}
+
+	// Wait and retry while no worker is available, as recommended by the API documentation
+	var waited time.Duration
+	for {
+		taskID, err := c.submitTask(ctx, body)
+		if err == nil || !errors.Is(err, ErrNoSlotAvailable) {
+			return taskID, err
+		}
+
+		delay := c.NoSlotRetryDelay
+		if delay <= 0 {
+			delay = defaultNoSlotRetryDelay
+		}
+		if waited+delay > c.NoSlotMaxWait {
+			return 0, err
+		}
+
+		c.logger(ctx).Warn("No slot available, retrying task creation", "delay", delay)
+		if serr := sleep(ctx, delay); serr != nil {
+			return 0, fmt.Errorf("%w (retry aborted: %w)", err, serr)
+		}
+		waited += delay
+	}
+}
 
-	var response map[string]interface{}
+// submitTask sends a createTask request with the given body and returns the task ID
+func (c *Client) submitTask(ctx context.Context, body CreateTaskRequest) (float64, error) {
+	var response CreateTaskResponse
 	err := c.makeRequest(ctx, "/createTask", body, &response)
 	if err != nil {
-		c.Logger.Printf("Failed to create task: %v\n", err)
+		c.logger(ctx).Error("Failed to create task", "error", err)
 		return 0, fmt.Errorf("failed to create task: %w", err)
 	}
 
 	// Check for API errors
-	if errMsg, ok := response["errorId"]; ok && errMsg.(float64) != 0 {
-		c.Logger.Printf("API error creating task: %s\n", response["errorDescription"].(string))
-		return 0, errors.New(response["errorDescription"].(string))
+	if apiErr := response.apiError(); apiErr != nil {
+		c.logger(ctx).Error("API error creating task", "error", apiErr)
+		return 0, apiErr
 	}
 
-	// Type assertion to float64
-	taskID, ok := response["taskId"].(float64)
-	if !ok {
-		c.Logger.Println("Failed to retrieve taskId from response")
+	if response.TaskID == 0 {
+		c.logger(ctx).Error("Failed to retrieve taskId from response")
 		return 0, errors.New("failed to retrieve taskId from response")
 	}
 
-	c.Logger.Printf("Task created successfully with ID: %f\n", taskID)
+	c.logger(ctx).Info("Task created", "task_id", response.TaskID)
 
-	return taskID, nil
+	return response.TaskID, nil
 }
 
 // getTaskResult checks the result of a given task
-func (c *Client) getTaskResult(ctx context.Context, taskID float64) (map[string]interface{}, error) {
-	body := map[string]interface{}{
-		"clientKey": c.APIKey,
-		"taskId":    taskID,
+func (c *Client) getTaskResult(ctx context.Context, taskID float64) (*TaskResultResponse, error) {
+	body := TaskResultRequest{
+		ClientKey: c.APIKey,
+		TaskID:    taskID,
 	}
 
-	c.Logger.Printf("Checking result for task ID: %f\n", taskID)
+	c.logger(ctx).Debug("Checking task result", "task_id", taskID)
 
-	var response map[string]interface{}
+	var response TaskResultResponse
 	err := c.makeRequest(ctx, "/getTaskResult", body, &response)
 	if err != nil {
-		c.Logger.Printf("Failed to get task result: %v\n", err)
+		c.logger(ctx).Error("Failed to get task result", "task_id", taskID, "error", err)
 		return nil, fmt.Errorf("failed to get task result: %w", err)
 	}
 
-	return response, nil
+	return &response, nil
 }
 
-// SendImage sends an image captcha to the AntiCaptcha API and waits for the solution
-func (c *Client) SendImage(imgString string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
+// waitForResult polls the result of a given task until it's ready and returns it.
+// It gives up with a *SolveTimeoutError when the context deadline passes, even mid-request, or after MaxAttempts checks.
+func (c *Client) waitForResult(ctx context.Context, taskID float64, poll PollConfig) (*TaskResult, error) {
+	var lastStatus TaskStatus
 
-	// Create the task and get the task ID
-	taskID, err := c.createTaskImage(ctx, imgString)
-	if err != nil {
-		c.Logger.Printf("Error sending image: %v\n", err)
-		return "", fmt.Errorf("failed to send image: %w", err)
+	if poll.FirstDelay > 0 {
+		delay := Backoff{Initial: poll.FirstDelay, Jitter: firstPollJitter}.Delay(0)
+		c.logger(ctx).Debug("Waiting before the first check", "task_id", taskID, "delay", delay)
+		if err := sleep(ctx, delay); err != nil {
+			c.logger(ctx).Warn("Stopped waiting for task", "task_id", taskID, "error", err)
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, &SolveTimeoutError{TaskID: taskID, Err: err}
+			}
+			return nil, fmt.Errorf("stopped waiting for task result: %w", err)
+		}
 	}
 
-	// Poll for the task result until it's ready
-	for {
+	for attempt := 0; ; attempt++ {
+		if poll.MaxAttempts > 0 && attempt >= poll.MaxAttempts {
+			c.logger(ctx).Error("Task was not solved in time", "task_id", taskID, "checks", attempt, "status", lastStatus)
+			return nil, &SolveTimeoutError{TaskID: taskID, LastStatus: lastStatus, Attempts: attempt}
+		}
+
 		response, err := c.getTaskResult(ctx, taskID)
 		if err != nil {
-			c.Logger.Printf("Error getting task result: %v\n", err)
-			return "", fmt.Errorf("failed to get task result: %w", err)
+			c.logger(ctx).Error("Error getting task result", "task_id", taskID, "error", err)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, &SolveTimeoutError{TaskID: taskID, LastStatus: lastStatus, Attempts: attempt, Err: err}
+			}
+			return nil, fmt.Errorf("failed to get task result: %w", err)
+		}
+
+		// Check for API errors, e.g. ERROR_CAPTCHA_UNSOLVABLE
+		if apiErr := response.apiError(); apiErr != nil {
+			c.logger(ctx).Error("API error getting task result", "task_id", taskID, "error", apiErr)
+			return nil, apiErr
 		}
 
-		if status, ok := response["status"].(string); ok && status == "ready" {
-			c.Logger.Printf("Task ID %f is ready with solution.\n", taskID)
-			solution, ok := response["solution"].(map[string]interface{})
-			if !ok {
-				c.Logger.Println("Invalid solution format in response")
-				return "", errors.New("invalid solution format in response")
+		switch response.Status {
+		case StatusReady:
+			c.logger(ctx).Debug("Task is ready", "task_id", taskID, "status", response.Status)
+			if response.Solution == nil {
+				c.logger(ctx).Error("Invalid solution format in response", "task_id", taskID)
+				return nil, errors.New("invalid solution format in response")
 			}
 
-			text, ok := solution["text"].(string)
-			if !ok {
-				c.Logger.Println("Text not found in solution")
-				return "", errors.New("text not found in solution")
+			return newTaskResult(taskID, response), nil
+		case StatusProcessing:
+			c.logger(ctx).Debug("Task is still processing", "task_id", taskID, "status", response.Status)
+		default:
+			// Unknown statuses are not terminal, so the task is polled again
+			c.logger(ctx).Warn("Unknown task status, polling again", "task_id", taskID, "status", response.Status)
+		}
+
+		lastStatus = response.Status
+		emit(ctx, Polling{TaskID: taskID, Attempt: attempt + 1, Status: response.Status})
+		if c.Progress != nil {
+			c.Progress(string(response.Status), attempt+1)
+		}
+		if err := sleep(ctx, poll.delay(attempt)); err != nil {
+			c.logger(ctx).Warn("Stopped waiting for task", "task_id", taskID, "error", err)
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, &SolveTimeoutError{TaskID: taskID, LastStatus: lastStatus, Attempts: attempt + 1, Err: err}
 			}
+			return nil, fmt.Errorf("stopped waiting for task result: %w", err)
+		}
+	}
+}
+
+// sleep waits for the given duration, returning the context error as soon as the context is done.
+// Unlike time.After, its timer is released right away when the context ends first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// solveTask creates a task, bounded by the client timeout, and waits for its result.
+// If the task was created but no result could be retrieved, the returned result only carries the task ID.
+// A positive pollInterval replaces the poll interval and backoff of the client for this task.
+// ctx carries the request ID assigned by the public entry point, so every request and log line of the solve has it.
+func (c *Client) solveTask(ctx context.Context, task taskPayload, softID int, pollInterval time.Duration) (result *TaskResult, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	ctx, span := c.startSpan(ctx, "anticaptcha solve")
+	start := time.Now()
+	c.stats.start()
+	defer func() {
+		elapsed := time.Since(start)
+		c.observeSolve(task, elapsed, result, err)
+		c.stats.finish(elapsed, result.cost(err), err)
+		span.SetAttribute("anticaptcha.solve_duration_ms", elapsed.Milliseconds())
+		endSpan(span, err)
+	}()
+
+	if c.dryRun != nil {
+		return c.dryRun.run(ctx, c, task)
+	}
+
+	if err := c.minBalance.check(ctx, c); err != nil {
+		return nil, err
+	}
+
+	taskID, err := c.createTask(ctx, task, softID, "")
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", ErrTimeout, err)
+		}
+		return nil, err
+	}
+
+	span.SetAttribute("anticaptcha.task_id", taskID)
+	emit(ctx, TaskCreated{TaskID: taskID})
+
+	// Poll for the task result until it's ready
+	result, err = c.waitForResult(ctx, taskID, c.taskPollConfig(task.taskType(), pollInterval))
+	if err != nil {
+		c.logger(ctx).Error("Task failed", "task_id", taskID, "elapsed", time.Since(start), "error", err)
+		return &TaskResult{TaskID: taskID}, err
+	}
+
+	c.logger(ctx).Info("Task solved", "task_id", taskID, "status", result.Status, "elapsed", time.Since(start), "cost", result.Cost)
+	return result, nil
+}
+
+// CreateTask submits a task of any type to the AntiCaptcha API and returns its ID.
+// The task is sent as is, so task types the library does not wrap yet can be used; it must at least set "type".
+// The client soft ID is sent along with it. Errors reported by the API are returned as *APIError.
+func (c *Client) CreateTask(ctx context.Context, task map[string]interface{}) (float64, error) {
+	if _, ok := task["type"].(string); !ok {
+		return 0, errors.New("task type is required")
+	}
+
+	return c.createTask(ctx, task, 0, "")
+}
+
+// GetTaskResult checks the result of a task once, without waiting for it to be ready.
+// The returned result carries the current status (StatusProcessing or StatusReady) and, once ready, the solution,
+// e.g. to resume a task by its stored ID. Errors reported by the API are returned as *APIError.
+func (c *Client) GetTaskResult(ctx context.Context, taskID float64) (*TaskResult, error) {
+	response, err := c.getTaskResult(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiErr := response.apiError(); apiErr != nil {
+		c.logger(ctx).Error("API error getting task result", "task_id", taskID, "error", apiErr)
+		return nil, apiErr
+	}
+
+	return newTaskResult(taskID, response), nil
+}
+
+// WaitForResult polls the result of a task created with CreateTask until it's ready, bounded by the client timeout,
+// and returns its solution. Errors reported by the API are returned as *APIError.
+func (c *Client) WaitForResult(ctx context.Context, taskID float64) (map[string]interface{}, error) {
+	ctx = withRequestID(ctx)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	result, err := c.waitForResult(ctx, taskID, c.pollConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Solution, nil
+}
+
+// WaitForResultWithConfig is like WaitForResult, but polls with the given configuration instead of the client one,
+// e.g. to use a different timing per task type, and returns the full task result.
+// It stops at the first error reported by the API, e.g. ERROR_CAPTCHA_UNSOLVABLE, returned as *APIError.
+func (c *Client) WaitForResultWithConfig(ctx context.Context, taskID float64, poll PollConfig) (*TaskResult, error) {
+	ctx = withRequestID(ctx)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.waitForResult(ctx, taskID, poll)
+}
+
+// solutionTokenKeys are the solution fields holding the main token, by task type:
+// image text, reCAPTCHA, HCaptcha, Turnstile and FunCaptcha tokens, GeeTest v4 and v3, and AWS WAF
+var solutionTokenKeys = []string{"text", "gRecaptchaResponse", "token", "pass_token", "validate", "captcha_voucher"}
 
-			c.Logger.Printf("Captcha solved successfully: %s\n", text)
-			return text, nil
+// solutionToken returns the main token of a solution of any task type, or an empty string if it has none
+func solutionToken(solution map[string]interface{}) string {
+	for _, key := range solutionTokenKeys {
+		if token, ok := solution[key].(string); ok && token != "" {
+			return token
 		}
+	}
+
+	return ""
+}
+
+// ResumeSolve polls an existing task to completion without creating it again, bounded by the client timeout,
+// e.g. with a task ID persisted before a restart. The solution token is the main field of the solution of any
+// task type, such as the image text or the captcha token, and the full solution is in Solution.Fields.
+// A task that expired or does not exist returns an error matching ErrTaskNotFound.
+func (c *Client) ResumeSolve(ctx context.Context, taskID float64) (Solution, error) {
+	ctx = withRequestID(ctx)
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	c.logger(ctx).Info("Resuming task", "task_id", taskID)
+
+	result, err := c.waitForResult(ctx, taskID, c.pollConfig())
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			c.logger(ctx).Error("Task to resume not found or expired", "task_id", taskID)
+			return Solution{TaskID: taskID}, fmt.Errorf("cannot resume task %.0f: %w", taskID, err)
+		}
+		return Solution{TaskID: taskID}, err
+	}
+
+	return newSolution(result, solutionToken(result.Solution)), nil
+}
+
+// ImageOptions holds the optional parameters of an image-to-text task.
+// Zero values are left out of the task, so the API defaults apply.
+type ImageOptions struct {
+	Phrase        bool   // The answer contains at least one space; sent as "phrase": true, the API default being false
+	CaseSensitive bool   // The answer is case-sensitive; sent as "case": true, otherwise workers may answer in lowercase
+	Numeric       int    // 1 for numbers only, 2 for any characters except numbers
+	Math          bool   // The image shows a math expression whose result is the answer
+	MinLength     int    // Minimum length of the answer, sent only when non-zero
+	MaxLength     int    // Maximum length of the answer, sent only when non-zero; must not be below MinLength
+	Comment       string // Instructions for the worker, e.g. "enter the red letters"
+	LanguagePool  string // Pool of workers to use, e.g. "en" or "rn", overriding the client language pool
+	SoftID        int    // Soft ID of the task, overriding the client soft ID
+
+	// PollInterval is the interval between checks of the task result, overriding the client poll interval and backoff
+	PollInterval time.Duration
+}
+
+// imageTask is the payload of an image-to-text task
+type imageTask struct {
+	Type         string `json:"type"`
+	Body         string `json:"body"`
+	Phrase       bool   `json:"phrase,omitempty"`
+	Case         bool   `json:"case,omitempty"`
+	Numeric      int    `json:"numeric,omitempty"`
+	Math         bool   `json:"math,omitempty"`
+	MinLength    int    `json:"minLength,omitempty"`
+	MaxLength    int    `json:"maxLength,omitempty"`
+	Comment      string `json:"comment,omitempty"`
+	LanguagePool string `json:"-"` // Sent with the createTask request rather than the task
+}
+
+// languagePool implements languagePooler
+func (t imageTask) languagePool() string {
+	return t.LanguagePool
+}
+
+// taskType implements taskPayload
+func (t imageTask) taskType() string {
+	return t.Type
+}
+
+// validate checks that the length constraints are consistent, without sending anything
+func (o ImageOptions) validate() error {
+	if o.MinLength < 0 || o.MaxLength < 0 {
+		return fmt.Errorf("invalid length constraint: minLength %d and maxLength %d must not be negative", o.MinLength, o.MaxLength)
+	}
+	if o.MaxLength > 0 && o.MinLength > o.MaxLength {
+		return fmt.Errorf("invalid length constraint: minLength %d is greater than maxLength %d", o.MinLength, o.MaxLength)
+	}
 
-		c.Logger.Printf("Task ID %f is still processing...\n", taskID)
-		time.Sleep(checkInterval)
+	return nil
+}
+
+// task builds the image-to-text task for the given base64 encoded image
+func (o ImageOptions) task(imgString string) imageTask {
+	return imageTask{
+		Type:         "ImageToTextTask",
+		Body:         imgString,
+		Phrase:       o.Phrase,
+		Case:         o.CaseSensitive,
+		Numeric:      o.Numeric,
+		Math:         o.Math,
+		MinLength:    o.MinLength,
+		MaxLength:    o.MaxLength,
+		Comment:      o.Comment,
+		LanguagePool: o.LanguagePool,
 	}
 }
 
+// SendImage is like SendImageContext but uses a background context
+func (c *Client) SendImage(imgString string) (string, float64, error) {
+	return c.SendImageContext(context.Background(), imgString)
+}
+
+// SendImageContext sends an image captcha to the AntiCaptcha API and waits for the solution.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (c *Client) SendImageContext(ctx context.Context, imgString string) (string, float64, error) {
+	return c.SendImageWithOptions(ctx, imgString, ImageOptions{})
+}
+
+// SendImageWithOptions sends an image captcha with the given options to the AntiCaptcha API and waits for the solution.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (c *Client) SendImageWithOptions(ctx context.Context, imgString string, opts ImageOptions) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := c.solveImage(ctx, imgString, opts)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	text, err := c.imageText(ctx, result)
+	return text, result.TaskID, err
+}
+
+// imageText extracts the text from an image-to-text task result
+func (c *Client) imageText(ctx context.Context, result *TaskResult) (string, error) {
+	text, ok := result.Solution["text"].(string)
+	if !ok {
+		c.logger(ctx).Error("Text not found in solution", "task_id", result.TaskID)
+		return "", errors.New("text not found in solution")
+	}
+
+	c.logger(ctx).Info("Captcha solved", "task_id", result.TaskID, "text", c.redact(text))
+	return text, nil
+}
+
+// SendImageDetailed sends an image captcha to the AntiCaptcha API and waits for the full task result,
+// including its cost and timing. If the task was created, the result carries its ID even on error.
+func (c *Client) SendImageDetailed(ctx context.Context, imgString string) (*TaskResult, error) {
+	return c.solveImage(withRequestID(ctx), imgString, ImageOptions{})
+}
+
+// ImageCaptcha represents an image captcha to solve, so it can be used as a Solver
+type ImageCaptcha struct {
+	Client  *Client
+	Body    string // Base64 encoded image
+	Options ImageOptions
+}
+
+// NewImageCaptcha creates a new ImageCaptcha for the given base64 encoded image
+func NewImageCaptcha(client *Client, imgString string) *ImageCaptcha {
+	return &ImageCaptcha{
+		Client: client,
+		Body:   imgString,
+	}
+}
+
+// SetOptions sets the optional parameters of the image-to-text task
+func (i *ImageCaptcha) SetOptions(opts ImageOptions) {
+	i.Options = opts
+}
+
+// Solve implements Solver, with the image text as the token
+func (i *ImageCaptcha) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	if err := i.Validate(); err != nil {
+		return Solution{}, err
+	}
+
+	result, err := i.Client.solveImage(ctx, i.Body, i.Options)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	text, err := i.Client.imageText(ctx, result)
+	return newSolution(result, text), err
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (i *ImageCaptcha) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	if err := i.Validate(); err != nil {
+		return 0, err
+	}
+
+	imgString, err := normalizeImage(i.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	i.Client.logger(ctx).Debug("Submitting image captcha task")
+
+	return i.Client.createTaskAsync(ctx, i.Options.task(imgString), i.Options.SoftID)
+}
+
+// Validate checks that the image is set and base64 encoded, without sending anything
+func (i *ImageCaptcha) Validate() error {
+	if i.Body == "" {
+		return missingField("body")
+	}
+	if err := i.Options.validate(); err != nil {
+		return err
+	}
+
+	_, err := normalizeImage(i.Body)
+	return err
+}
+
+// solveImage creates an image-to-text task with the given options and waits for its result
+func (c *Client) solveImage(ctx context.Context, imgString string, opts ImageOptions) (*TaskResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	imgString, err := normalizeImage(imgString)
+	if err != nil {
+		return nil, err
+	}
+
+	task := opts.task(imgString)
+
+	var key string
+	if c.inflight != nil || c.imageCache != nil {
+		key, err = imageKey(task, opts.SoftID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash image: %w", err)
+		}
+	}
+
+	if c.imageCache != nil {
+		if result, ok := c.imageCache.get(key, time.Now()); ok {
+			c.logger(ctx).Debug("Image solution found in cache", "task_id", result.TaskID)
+			return result, nil
+		}
+	}
+
+	solve := func(ctx context.Context) (*TaskResult, error) {
+		c.logger(ctx).Debug("Creating image captcha task")
+		result, err := c.solveTask(ctx, task, opts.SoftID, opts.PollInterval)
+		if err == nil && c.imageCache != nil {
+			c.imageCache.add(key, result, time.Now())
+		}
+		return result, err
+	}
+
+	var result *TaskResult
+	if c.inflight != nil {
+		// A caller without a deadline of its own waits for the solve at most the client timeout
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadline = time.Now().Add(c.solveTimeout())
+		}
+
+		var shared bool
+		result, shared, err = c.inflight.do(ctx, key, deadline, solve)
+		if shared {
+			c.logger(ctx).Debug("Shared the solve of an identical image", "task_id", result.id())
+		}
+	} else {
+		result, err = solve(ctx)
+	}
+	if err != nil {
+		c.logger(ctx).Error("Error sending image", "task_id", result.id(), "error", err)
+		return result, fmt.Errorf("failed to send image: %w", err)
+	}
+
+	return result, nil
+}
+
+// HCaptchaSolution holds the token returned for an HCaptcha task with the values it must be submitted with.
+// It is returned by value and never stored on the task configuration, so concurrent solves do not share it.
+type HCaptchaSolution struct {
+	GRecaptchaResponse string
+	UserAgent          string // Empty if the API did not return one
+	RespKey            string // Empty if the API did not return one
+
+	Raw map[string]interface{} // The full solution object as received, including fields not modeled above
+}
+
 // HCaptchaProxyless represents the configuration for an HCaptcha proxyless task
 type HCaptchaProxyless struct {
 	Client            *Client
@@ -208,9 +1109,8 @@ type HCaptchaProxyless struct {
 	IsInvisible       bool
 	IsEnterprise      bool
 	EnterprisePayload map[string]interface{}
+	PollInterval      time.Duration
 	SoftID            int
-	UserAgent         string
-	RespKey           string
 }
 
 // NewHCaptchaProxyless creates a new HCaptchaProxyless task configuration
@@ -254,75 +1154,268 @@ func (h *HCaptchaProxyless) SetSoftID(softID int) {
 	h.SoftID = softID
 }
 
-// SolveAndReturnSolution creates the task, waits for the solution, and returns it
-func (h *HCaptchaProxyless) SolveAndReturnSolution() (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-	defer cancel()
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (h *HCaptchaProxyless) SetPollInterval(interval time.Duration) {
+	h.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (h *HCaptchaProxyless) SolveAndReturnSolution() (HCaptchaSolution, float64, error) {
+	return h.SolveAndReturnSolutionContext(context.Background())
+}
 
-	body := map[string]interface{}{
-		"clientKey": h.Client.APIKey,
-		"task": map[string]interface{}{
-			"type":              "HCaptchaTaskProxyless",
-			"websiteURL":        h.WebsiteURL,
-			"websiteKey":        h.WebsiteKey,
-			"isInvisible":       h.IsInvisible,
-			"isEnterprise":      h.IsEnterprise,
-			"enterprisePayload": h.EnterprisePayload,
-		},
-		"softId": h.SoftID,
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns it.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (h *HCaptchaProxyless) SolveAndReturnSolutionContext(ctx context.Context) (HCaptchaSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := h.solve(ctx)
+	if err != nil {
+		return HCaptchaSolution{}, result.id(), err
 	}
 
-	h.Client.Logger.Println("Creating HCaptcha proxyless task...")
+	solution, err := h.Client.hcaptchaSolution(ctx, result)
+	return solution, result.TaskID, err
+}
 
-	var response map[string]interface{}
-	err := h.Client.makeRequest(ctx, "/createTask", body, &response)
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (h *HCaptchaProxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return h.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (h *HCaptchaProxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := h.payload(ctx)
 	if err != nil {
-		h.Client.Logger.Printf("Failed to create task: %v\n", err)
-		return "", fmt.Errorf("failed to create task: %w", err)
+		return nil, err
 	}
 
-	if errMsg, ok := response["errorId"]; ok && errMsg.(float64) != 0 {
-		h.Client.Logger.Printf("API error creating task: %s\n", response["errorDescription"].(string))
-		return "", errors.New(response["errorDescription"].(string))
+	h.Client.logger(ctx).Debug("Creating HCaptcha proxyless task")
+
+	return h.Client.solveTask(ctx, task, h.SoftID, h.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (h *HCaptchaProxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := h.payload(ctx)
+	if err != nil {
+		return 0, err
 	}
 
-	taskID, ok := response["taskId"].(float64)
-	if !ok {
-		h.Client.Logger.Println("Failed to retrieve taskId from response")
-		return "", errors.New("failed to retrieve taskId from response")
+	h.Client.logger(ctx).Debug("Submitting HCaptcha proxyless task")
+
+	return h.Client.createTaskAsync(ctx, task, h.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (h *HCaptchaProxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
 	}
 
-	h.Client.Logger.Printf("Task created successfully with ID: %f\n", taskID)
+	return h.task(), nil
+}
 
-	// Poll for the task result until it's ready
-	for {
-		result, err := h.Client.getTaskResult(ctx, taskID)
-		if err != nil {
-			h.Client.Logger.Printf("Error getting task result: %v\n", err)
-			return "", fmt.Errorf("failed to get task result: %w", err)
-		}
+// Validate checks that the required fields are set, without sending anything
+func (h *HCaptchaProxyless) Validate() error {
+	if err := validateWebsiteURL(h.WebsiteURL); err != nil {
+		return err
+	}
+	if h.WebsiteKey == "" {
+		return missingField("websiteKey")
+	}
 
-		if status, ok := result["status"].(string); ok && status == "ready" {
-			h.Client.Logger.Printf("Task ID %f is ready with solution.\n", taskID)
-			solution, ok := result["solution"].(map[string]interface{})
-			if !ok {
-				h.Client.Logger.Println("Invalid solution format in response")
-				return "", errors.New("invalid solution format in response")
-			}
+	return nil
+}
 
-			gResponse, ok := solution["gRecaptchaResponse"].(string)
-			if !ok {
-				h.Client.Logger.Println("gRecaptchaResponse not found in solution")
-				return "", errors.New("gRecaptchaResponse not found in solution")
-			}
+// Solve implements Solver, with the gRecaptchaResponse as the token
+func (h *HCaptchaProxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := h.solve(ctx)
+	return h.Client.hcaptchaSolve(ctx, result, err)
+}
 
-			h.UserAgent = solution["userAgent"].(string)
-			h.RespKey = solution["respKey"].(string)
-			h.Client.Logger.Printf("HCaptcha solved successfully: %s\n", gResponse)
-			return gResponse, nil
-		}
+// hcaptchaTask is the payload of an HCaptcha task
+type hcaptchaTask struct {
+	Type              string                 `json:"type"`
+	WebsiteURL        string                 `json:"websiteURL"`
+	WebsiteKey        string                 `json:"websiteKey"`
+	IsInvisible       bool                   `json:"isInvisible"`
+	IsEnterprise      bool                   `json:"isEnterprise"`
+	EnterprisePayload map[string]interface{} `json:"enterprisePayload"`
+	UserAgent         string                 `json:"userAgent,omitempty"`
+	Cookies           string                 `json:"cookies,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t hcaptchaTask) taskType() string {
+	return t.Type
+}
+
+// task builds the HCaptcha proxyless task
+func (h *HCaptchaProxyless) task() hcaptchaTask {
+	return hcaptchaTask{
+		Type:              "HCaptchaTaskProxyless",
+		WebsiteURL:        h.WebsiteURL,
+		WebsiteKey:        h.WebsiteKey,
+		IsInvisible:       h.IsInvisible,
+		IsEnterprise:      h.IsEnterprise,
+		EnterprisePayload: h.EnterprisePayload,
+	}
+}
+
+// HCaptchaTask represents the configuration for an HCaptcha task solved through a proxy
+type HCaptchaTask struct {
+	HCaptchaProxyless
+	Proxy     Proxy
+	UserAgent string
+	Cookies   string // "name1=value1; name2=value2"
+}
+
+// NewHCaptchaTask creates a new HCaptchaTask configuration
+func NewHCaptchaTask(client *Client) *HCaptchaTask {
+	return &HCaptchaTask{
+		HCaptchaProxyless: *NewHCaptchaProxyless(client),
+	}
+}
+
+// SetProxy sets the proxy the worker solves the HCaptcha through
+func (h *HCaptchaTask) SetProxy(proxy Proxy) {
+	h.Proxy = proxy
+}
+
+// SetUserAgent sets the browser user agent the worker solves the HCaptcha with
+func (h *HCaptchaTask) SetUserAgent(userAgent string) {
+	h.UserAgent = userAgent
+}
+
+// SetCookies sets the cookies the worker solves the HCaptcha with, e.g. the session cookie of the website
+func (h *HCaptchaTask) SetCookies(cookies map[string]string) {
+	h.Cookies = formatCookies(cookies)
+}
+
+// SetCookieString sets the cookies the worker solves the HCaptcha with as a raw "name1=value1; name2=value2" string
+func (h *HCaptchaTask) SetCookieString(cookies string) {
+	h.Cookies = cookies
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (h *HCaptchaTask) SolveAndReturnSolution() (HCaptchaSolution, float64, error) {
+	return h.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns it.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (h *HCaptchaTask) SolveAndReturnSolutionContext(ctx context.Context) (HCaptchaSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := h.solve(ctx)
+	if err != nil {
+		return HCaptchaSolution{}, result.id(), err
+	}
+
+	solution, err := h.Client.hcaptchaSolution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (h *HCaptchaTask) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return h.solve(withRequestID(ctx))
+}
 
-		h.Client.Logger.Printf("Task ID %f is still processing...\n", taskID)
-		time.Sleep(checkInterval)
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (h *HCaptchaTask) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := h.payload(ctx)
+	if err != nil {
+		return nil, err
 	}
+
+	h.Client.logger(ctx).Debug("Creating HCaptcha task")
+
+	return h.Client.solveTask(ctx, task, h.SoftID, h.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (h *HCaptchaTask) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := h.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	h.Client.logger(ctx).Debug("Submitting HCaptcha task")
+
+	return h.Client.createTaskAsync(ctx, task, h.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (h *HCaptchaTask) payload(ctx context.Context) (taskPayload, error) {
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := h.HCaptchaProxyless.task()
+	task.Type = "HCaptchaTask"
+	task.UserAgent = h.UserAgent
+	task.Cookies = h.Cookies
+	proxy, err := h.Client.drawProxy(ctx, h.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	task.proxyPayload = proxy
+
+	return task, nil
+}
+
+// Validate checks that the required fields, the proxy and the user agent are set, without sending anything
+func (h *HCaptchaTask) Validate() error {
+	if err := h.HCaptchaProxyless.Validate(); err != nil {
+		return err
+	}
+	if err := h.Client.validateProxy(h.Proxy); err != nil {
+		return err
+	}
+	if h.UserAgent == "" {
+		return missingField("userAgent")
+	}
+
+	return validateCookies(h.Cookies)
+}
+
+// Solve implements Solver, with the gRecaptchaResponse as the token
+func (h *HCaptchaTask) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := h.solve(ctx)
+	return h.Client.hcaptchaSolve(ctx, result, err)
+}
+
+// hcaptchaSolve builds the Solver solution of an HCaptcha task result
+func (c *Client) hcaptchaSolve(ctx context.Context, result *TaskResult, err error) (Solution, error) {
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := c.hcaptchaSolution(ctx, result)
+	return newSolution(result, solution.GRecaptchaResponse), err
+}
+
+// hcaptchaSolution extracts the HCaptcha solution from a task result
+func (c *Client) hcaptchaSolution(ctx context.Context, result *TaskResult) (HCaptchaSolution, error) {
+	gResponse, ok := result.Solution["gRecaptchaResponse"].(string)
+	if !ok {
+		c.logger(ctx).Error("gRecaptchaResponse not found in solution", "task_id", result.TaskID)
+		return HCaptchaSolution{}, errors.New("gRecaptchaResponse not found in solution")
+	}
+
+	// userAgent and respKey are omitted for some invisible and enterprise tasks, so leave them empty when absent
+	solution := HCaptchaSolution{GRecaptchaResponse: gResponse, Raw: result.Solution}
+	solution.UserAgent, _ = result.Solution["userAgent"].(string)
+	solution.RespKey, _ = result.Solution["respKey"].(string)
+
+	c.logger(ctx).Info("HCaptcha solved", "task_id", result.TaskID, "token", c.redact(gResponse))
+	return solution, nil
 }
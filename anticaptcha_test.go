@@ -0,0 +1,223 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestSolveCancelledMidPoll checks that cancelling the context of a solve while it waits between polls
+// returns the context error promptly, without waiting for the poll interval to elapse
+func TestSolveCancelledMidPoll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+		case "/getTaskResult":
+			fmt.Fprint(w, `{"errorId":0,"status":"processing"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	const interval = 5 * time.Second
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithPollInterval(interval),
+		WithFirstPollDelay(-1),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, taskID, err := client.SendImageContext(ctx, "aGVsbG8=")
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected an error matching context.Canceled, got %v", err)
+	}
+	if taskID != 7 {
+		t.Errorf("expected task ID 7, got %v", taskID)
+	}
+	if elapsed >= interval/2 {
+		t.Errorf("solve returned after %s, expected well under the %s poll interval", elapsed, interval)
+	}
+}
+
+// TestCreateTaskNotRetriedOnceSent checks that a failed task creation isn't retried once the request was sent,
+// as it may have created a billed task, while read-only requests are still retried
+func TestCreateTaskNotRetriedOnceSent(t *testing.T) {
+	var creates, balances int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			atomic.AddInt32(&creates, 1)
+		case "/getBalance":
+			atomic.AddInt32(&balances, 1)
+		}
+		http.Error(w, "upstream timeout", http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithMaxRetries(2),
+		WithRetryBackoff(Backoff{Initial: time.Millisecond}),
+	)
+
+	if _, _, err := client.SendImageContext(context.Background(), "aGVsbG8="); err == nil {
+		t.Fatal("expected task creation to fail")
+	}
+	if n := atomic.LoadInt32(&creates); n != 1 {
+		t.Errorf("expected a single task creation attempt, got %d", n)
+	}
+
+	if _, err := client.GetBalance(context.Background()); err == nil {
+		t.Fatal("expected the balance request to fail")
+	}
+	if n := atomic.LoadInt32(&balances); n != 3 {
+		t.Errorf("expected the balance request to be tried 3 times, got %d", n)
+	}
+}
+
+// TestCreateTaskRetriedWhenNotSent checks that task creation is retried when the connection is refused
+func TestCreateTaskRetriedWhenNotSent(t *testing.T) {
+	var attempts int32
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL("http://anticaptcha.invalid"),
+		WithMaxRetries(2),
+		WithRetryBackoff(Backoff{Initial: time.Millisecond}),
+		WithInterceptor(func(next RoundTripperFunc) RoundTripperFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&attempts, 1)
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+			}
+		}),
+	)
+
+	if _, _, err := client.SendImageContext(context.Background(), "aGVsbG8="); !errors.Is(err, syscall.ECONNREFUSED) {
+		t.Fatalf("expected an error matching ECONNREFUSED, got %v", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 3 {
+		t.Errorf("expected task creation to be tried 3 times, got %d", n)
+	}
+}
+
+// TestCreateTaskRetriedWhenRejected checks that task creation rejected with a 429 is retried after the Retry-After delay
+func TestCreateTaskRetriedWhenRejected(t *testing.T) {
+	var creates int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			if atomic.AddInt32(&creates, 1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+		case "/getTaskResult":
+			fmt.Fprint(w, `{"errorId":0,"status":"ready","solution":{"text":"abc"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithMaxRetries(2),
+		WithRetryBackoff(Backoff{Initial: time.Millisecond}),
+		WithFirstPollDelay(-1),
+	)
+
+	start := time.Now()
+	text, taskID, err := client.SendImageContext(context.Background(), "aGVsbG8=")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "abc" || taskID != 7 {
+		t.Errorf("expected text abc for task 7, got %q for task %v", text, taskID)
+	}
+	if n := atomic.LoadInt32(&creates); n != 2 {
+		t.Errorf("expected 2 task creation attempts, got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected the retry to wait for the Retry-After delay, took %s", elapsed)
+	}
+}
+
+// TestSolve5xx checks that 5xx responses are retried when polling, but not when creating the task
+// unless the server rejected it with Retry-After
+func TestSolve5xx(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var creates int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/createTask" {
+					atomic.AddInt32(&creates, 1)
+				}
+				http.Error(w, "unavailable", status)
+			}))
+			defer server.Close()
+
+			client := NewClientWithOptions("test-key",
+				WithSilentLogging(),
+				WithBaseURL(server.URL),
+				WithHTTPClient(server.Client()),
+				WithMaxRetries(2),
+				WithRetryBackoff(Backoff{Initial: time.Millisecond}),
+			)
+
+			_, _, err := client.SendImageContext(context.Background(), "aGVsbG8=")
+			var httpErr *HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != status {
+				t.Fatalf("expected an *HTTPError with status %d, got %v", status, err)
+			}
+			if n := atomic.LoadInt32(&creates); n != 1 {
+				t.Errorf("expected a single task creation attempt, got %d", n)
+			}
+		})
+	}
+
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+		case "/getTaskResult":
+			if atomic.AddInt32(&polls, 1) == 1 {
+				http.Error(w, "bad gateway", http.StatusBadGateway)
+				return
+			}
+			fmt.Fprint(w, `{"errorId":0,"status":"ready","solution":{"text":"abc"}}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithRetryBackoff(Backoff{Initial: time.Millisecond}),
+		WithFirstPollDelay(-1),
+	)
+	if text, _, err := client.SendImageContext(context.Background(), "aGVsbG8="); err != nil || text != "abc" {
+		t.Fatalf("expected the 502 on the poll to be retried, got %q, %v", text, err)
+	}
+}
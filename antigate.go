@@ -0,0 +1,281 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AntiGate represents the configuration for an AntiGate task running a custom scenario template
+type AntiGate struct {
+	Client            *Client
+	WebsiteURL        string
+	TemplateName      string
+	Variables         map[string]interface{}
+	DomainsOfInterest []string
+	Proxyless         bool
+	Proxy             Proxy
+	PollInterval      time.Duration
+	SoftID            int
+}
+
+// antiGateTaskType is the type of AntiGate tasks, run with or without a proxy
+const antiGateTaskType = "AntiGateTask"
+
+// antiGateTask is the payload of an AntiGate task
+type antiGateTask struct {
+	Type              string                 `json:"type"`
+	WebsiteURL        string                 `json:"websiteURL"`
+	TemplateName      string                 `json:"templateName"`
+	Variables         map[string]interface{} `json:"variables"`
+	DomainsOfInterest []string               `json:"domainsOfInterest,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t antiGateTask) taskType() string {
+	return t.Type
+}
+
+// NewAntiGate creates a new AntiGate task configuration (proxyless by default)
+func NewAntiGate(client *Client) *AntiGate {
+	return &AntiGate{
+		Client:    client,
+		Variables: make(map[string]interface{}),
+		Proxyless: true,
+		SoftID:    0,
+	}
+}
+
+// SetWebsiteURL sets the website URL the scenario starts at
+func (a *AntiGate) SetWebsiteURL(url string) {
+	a.WebsiteURL = url
+}
+
+// SetTemplateName sets the name of the scenario template
+func (a *AntiGate) SetTemplateName(name string) {
+	a.TemplateName = name
+}
+
+// SetVariables sets the variables of the scenario template
+func (a *AntiGate) SetVariables(variables map[string]interface{}) {
+	a.Variables = variables
+}
+
+// SetDomainsOfInterest sets the domains whose cookies and local storage are returned with the solution
+func (a *AntiGate) SetDomainsOfInterest(domains []string) {
+	a.DomainsOfInterest = domains
+}
+
+// SetProxyless sets whether the scenario runs without a proxy
+func (a *AntiGate) SetProxyless(proxyless bool) {
+	a.Proxyless = proxyless
+}
+
+// SetProxy sets the proxy used to run the scenario and disables proxyless mode
+func (a *AntiGate) SetProxy(proxy Proxy) {
+	a.Proxy = proxy
+	a.Proxyless = false
+}
+
+// SetSoftID sets the soft ID for the AntiGate task
+func (a *AntiGate) SetSoftID(softID int) {
+	a.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (a *AntiGate) SetPollInterval(interval time.Duration) {
+	a.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (a *AntiGate) SolveAndReturnSolution() (map[string]interface{}, float64, error) {
+	return a.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the scenario to finish, and returns its solution.
+// The solution shape is defined by the scenario template, so it is returned as a map.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (a *AntiGate) SolveAndReturnSolutionContext(ctx context.Context) (map[string]interface{}, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return nil, result.id(), err
+	}
+
+	a.Client.logger(ctx).Info("AntiGate scenario finished", "task_id", result.TaskID)
+	return result.Solution, result.TaskID, nil
+}
+
+// Solve implements Solver. The solution shape is defined by the scenario template, so the token is left empty
+// and the solution is only available through Fields.
+func (a *AntiGate) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := a.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	a.Client.logger(ctx).Info("AntiGate scenario finished", "task_id", result.TaskID)
+	return newSolution(result, ""), nil
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (a *AntiGate) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return a.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (a *AntiGate) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := a.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Client.logger(ctx).Debug("Creating AntiGate task", "template", a.TemplateName)
+
+	return a.Client.solveTask(ctx, task, a.SoftID, a.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (a *AntiGate) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := a.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	a.Client.logger(ctx).Debug("Submitting AntiGate task", "template", a.TemplateName)
+
+	return a.Client.createTaskAsync(ctx, task, a.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (a *AntiGate) payload(ctx context.Context) (taskPayload, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	task, err := a.task(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// Start creates the task and returns its ID without waiting for the scenario to finish, so variables can be
+// pushed to the running scenario with Client.PushAntiGateVariable before its result is awaited with Wait.
+func (a *AntiGate) Start(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := a.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	a.Client.logger(ctx).Debug("Starting AntiGate task", "template", a.TemplateName)
+
+	return a.Client.createTask(ctx, task, a.SoftID, "")
+}
+
+// Wait waits for the scenario of a task created with Start to finish, bounded by the client timeout,
+// and returns its solution
+func (a *AntiGate) Wait(ctx context.Context, taskID float64) (map[string]interface{}, error) {
+	ctx = withRequestID(ctx)
+	ctx, cancel := a.Client.withTimeout(ctx)
+	defer cancel()
+
+	result, err := a.Client.waitForResult(ctx, taskID, a.Client.taskPollConfig(antiGateTaskType, a.PollInterval))
+	if err != nil {
+		a.Client.logger(ctx).Error("AntiGate scenario failed", "task_id", taskID, "error", err)
+		return nil, err
+	}
+
+	a.Client.logger(ctx).Info("AntiGate scenario finished", "task_id", taskID)
+	return result.Solution, nil
+}
+
+// task builds the AntiGate task, drawing its proxy unless proxyless
+func (a *AntiGate) task(ctx context.Context) (antiGateTask, error) {
+	task := antiGateTask{
+		Type:              antiGateTaskType,
+		WebsiteURL:        a.WebsiteURL,
+		TemplateName:      a.TemplateName,
+		Variables:         a.Variables,
+		DomainsOfInterest: a.DomainsOfInterest,
+	}
+	if !a.Proxyless {
+		proxy, err := a.Client.drawProxy(ctx, a.Proxy)
+		if err != nil {
+			return antiGateTask{}, err
+		}
+		task.proxyPayload = proxy
+	}
+
+	return task, nil
+}
+
+// Validate checks that the required fields, and the proxy unless proxyless, are set, without sending anything
+func (a *AntiGate) Validate() error {
+	if err := validateWebsiteURL(a.WebsiteURL); err != nil {
+		return err
+	}
+	if a.TemplateName == "" {
+		return missingField("templateName")
+	}
+	if !a.Proxyless {
+		return a.Client.validateProxy(a.Proxy)
+	}
+
+	return nil
+}
+
+// pushVariableRequest is the body of a pushAntiGateVariable request
+type pushVariableRequest struct {
+	ClientKey string      `json:"clientKey"`
+	TaskID    float64     `json:"taskId"`
+	Name      string      `json:"name"`
+	Value     interface{} `json:"value"`
+}
+
+// PushAntiGateVariable sends the value of a variable to a running AntiGate scenario,
+// e.g. a code received by SMS after the scenario started.
+func (c *Client) PushAntiGateVariable(ctx context.Context, taskID float64, name string, value interface{}) error {
+	if name == "" {
+		return errors.New("variable name is required")
+	}
+
+	body := pushVariableRequest{
+		ClientKey: c.APIKey,
+		TaskID:    taskID,
+		Name:      name,
+		Value:     value,
+	}
+
+	c.logger(ctx).Debug("Pushing variable", "task_id", taskID, "name", name)
+
+	var response statusResponse
+	err := c.makeRequest(ctx, "/pushAntiGateVariable", body, &response)
+	if err != nil {
+		c.logger(ctx).Error("Failed to push variable", "task_id", taskID, "name", name, "error", err)
+		return fmt.Errorf("failed to push variable %q: %w", name, err)
+	}
+
+	// Check for API errors
+	if apiErr := response.apiError(); apiErr != nil {
+		c.logger(ctx).Error("API error pushing variable", "task_id", taskID, "name", name, "error", apiErr)
+		return fmt.Errorf("failed to push variable %q: %w", name, apiErr)
+	}
+
+	if response.Status != "success" {
+		c.logger(ctx).Warn("Variable was not accepted", "task_id", taskID, "name", name, "status", response.Status)
+		return fmt.Errorf("failed to push variable %q: status %q", name, response.Status)
+	}
+
+	c.logger(ctx).Info("Variable pushed", "task_id", taskID, "name", name)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package anticaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestAntiGateStartPushWait checks that a variable can be pushed between starting a scenario and waiting for it
+func TestAntiGateStartPushWait(t *testing.T) {
+	var pushed int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			fmt.Fprint(w, `{"errorId":0,"taskId":42}`)
+		case "/pushAntiGateVariable":
+			var body pushVariableRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.TaskID != 42 || body.Name != "sms_code" {
+				t.Errorf("unexpected push request %+v (%v)", body, err)
+			}
+			atomic.StoreInt32(&pushed, 1)
+			fmt.Fprint(w, `{"errorId":0,"status":"success"}`)
+		case "/getTaskResult":
+			if atomic.LoadInt32(&pushed) == 0 {
+				fmt.Fprint(w, `{"errorId":0,"status":"processing"}`)
+				return
+			}
+			fmt.Fprint(w, `{"errorId":0,"status":"ready","solution":{"url":"https://website.com/home"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions("test-key", WithSilentLogging(), WithBaseURL(server.URL), WithFirstPollDelay(-1))
+	antiGate := NewAntiGate(client)
+	antiGate.SetWebsiteURL("https://website.com/login")
+	antiGate.SetTemplateName("Sign-in with SMS code")
+
+	ctx := context.Background()
+	taskID, err := antiGate.Start(ctx)
+	if err != nil || taskID != 42 {
+		t.Fatalf("Start = %v, %v; want 42, nil", taskID, err)
+	}
+	if err := client.PushAntiGateVariable(ctx, taskID, "sms_code", "1234"); err != nil {
+		t.Fatalf("PushAntiGateVariable: %v", err)
+	}
+
+	solution, err := antiGate.Wait(ctx, taskID)
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if solution["url"] != "https://website.com/home" {
+		t.Errorf("unexpected solution %v", solution)
+	}
+}
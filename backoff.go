@@ -0,0 +1,67 @@
+package anticaptcha
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff describes an exponential backoff with jitter
+type Backoff struct {
+	Initial    time.Duration // Delay before the first retry; a default is used if zero or negative
+	Max        time.Duration // Upper bound of the delay, before jitter
+	Multiplier float64       // Factor applied to the delay after each attempt
+	Jitter     float64       // Random spread applied to each delay, e.g. 0.2 for ±20%; capped at 1
+}
+
+// DefaultPollBackoff is the default poll backoff of a client. Without Initial, it starts at the poll interval
+// (2 seconds by default) and slows down up to 10 seconds, with ±20% jitter.
+var DefaultPollBackoff = Backoff{
+	Max:        10 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// DefaultRetryBackoff retries failed requests after 500 milliseconds and slows down up to 5 seconds, with ±20% jitter
+var DefaultRetryBackoff = Backoff{
+	Initial:    500 * time.Millisecond,
+	Max:        5 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Delay returns the delay to wait after the given attempt, starting at 0.
+// A backoff without Initial starts at the default poll interval of 2 seconds, so it never turns into a busy loop.
+func (b Backoff) Delay(attempt int) time.Duration {
+	return b.delay(attempt, checkInterval)
+}
+
+// delay returns the delay to wait after the given attempt, starting at fallback if Initial is not positive
+func (b Backoff) delay(attempt int, fallback time.Duration) time.Duration {
+	initial := b.Initial
+	if initial <= 0 {
+		initial = fallback
+	}
+
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(initial) * math.Pow(multiplier, float64(attempt))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	if b.Jitter > 0 {
+		jitter := math.Min(b.Jitter, 1)
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	// Guard against a negative delay from rounding
+	if delay < 0 {
+		delay = 0
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,53 @@
+package anticaptcha
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBackoffZeroInitial checks that a backoff without Initial falls back to a default instead of a busy loop
+func TestBackoffZeroInitial(t *testing.T) {
+	if d := (Backoff{}).Delay(0); d != checkInterval {
+		t.Errorf("Delay of a zero backoff = %s, want %s", d, checkInterval)
+	}
+
+	poll := PollConfig{Backoff: &Backoff{Max: time.Second}}
+	if d := poll.delay(0); d != time.Second {
+		t.Errorf("poll delay with only Max set = %s, want %s", d, time.Second)
+	}
+
+	if d := (Backoff{}).delay(3, DefaultRetryBackoff.Initial); d != DefaultRetryBackoff.Initial {
+		t.Errorf("retry delay of a zero backoff = %s, want %s", d, DefaultRetryBackoff.Initial)
+	}
+}
+
+// TestBackoffLargeJitter checks that a jitter above 1 never produces a negative delay
+func TestBackoffLargeJitter(t *testing.T) {
+	b := Backoff{Initial: time.Second, Jitter: 5}
+	for i := 0; i < 1000; i++ {
+		if d := b.Delay(0); d < 0 || d > 2*time.Second {
+			t.Fatalf("Delay with jitter 5 = %s, want between 0 and 2s", d)
+		}
+	}
+}
+
+// TestDefaultPollBackoff checks that a client backs off from its poll interval by default, never polling faster
+func TestDefaultPollBackoff(t *testing.T) {
+	poll := NewClientWithOptions("test-key", WithSilentLogging()).pollConfig()
+	if d := poll.delay(0); d != checkInterval {
+		t.Errorf("first poll delay = %s, want %s", d, checkInterval)
+	}
+	for attempt := 0; attempt < 20; attempt++ {
+		if d := poll.delay(attempt); d < checkInterval || d > 12*time.Second {
+			t.Fatalf("poll delay after attempt %d = %s, want between %s and 12s", attempt, d, checkInterval)
+		}
+	}
+	if d := poll.delay(10); d < 8*time.Second {
+		t.Errorf("poll delay after attempt 10 = %s, want at least 8s", d)
+	}
+
+	fixed := NewClientWithOptions("test-key", WithPollBackoff(Backoff{}), WithPollInterval(time.Second)).pollConfig()
+	if d := fixed.delay(10); d != time.Second {
+		t.Errorf("poll delay with a zero backoff = %s, want a fixed %s", d, time.Second)
+	}
+}
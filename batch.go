@@ -0,0 +1,63 @@
+package anticaptcha
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchResult represents the outcome of a single image of a batch
+type BatchResult struct {
+	Index    int // Position of the image in the batch
+	Solution string
+	TaskID   float64
+	Err      error
+}
+
+// SendImageBatch solves the given base64 encoded images through a pool of at most concurrency workers.
+// Results are returned in the order of the input, each carrying its own error, so one failed image
+// does not abort the batch. If the context is cancelled, pending images fail with the context error,
+// and that error is also returned.
+func (c *Client) SendImageBatch(ctx context.Context, imgs []string, concurrency int) ([]BatchResult, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(imgs) {
+		concurrency = len(imgs)
+	}
+
+	c.logger(ctx).Debug("Solving image batch", "images", len(imgs), "workers", concurrency)
+
+	results := make([]BatchResult, len(imgs))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				text, taskID, err := c.SendImageContext(ctx, imgs[i])
+				results[i] = BatchResult{Index: i, Solution: text, TaskID: taskID, Err: err}
+			}
+		}()
+	}
+
+	// Feed the workers until every image is dispatched or the context is done
+feed:
+	for i := range imgs {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			for j := i; j < len(imgs); j++ {
+				results[j] = BatchResult{Index: j, Err: ctx.Err()}
+			}
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	c.logger(ctx).Info("Image batch finished", "images", len(imgs))
+
+	return results, ctx.Err()
+}
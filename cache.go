@@ -0,0 +1,96 @@
+package anticaptcha
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// imageCache is an LRU cache of image solutions, keyed by imageKey, whose entries expire after a TTL
+type imageCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // Most recently used entries first
+	entries map[string]*list.Element
+}
+
+// imageCacheEntry is a cached solution
+type imageCacheEntry struct {
+	key     string
+	result  *TaskResult
+	expires time.Time
+}
+
+// newImageCache creates an empty cache holding up to size solutions for ttl; a zero ttl never expires them
+func newImageCache(size int, ttl time.Duration) *imageCache {
+	return &imageCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the cached solution of a key, if present and not expired
+func (c *imageCache) get(key string, now time.Time) (*TaskResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*imageCacheEntry)
+	if c.ttl > 0 && now.After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.result, true
+}
+
+// add caches the solution of a key, evicting the least recently used solution if the cache is full
+func (c *imageCache) add(key string, result *TaskResult, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+	for c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*imageCacheEntry).key)
+	}
+
+	c.entries[key] = c.order.PushFront(&imageCacheEntry{
+		key:     key,
+		result:  result,
+		expires: now.Add(c.ttl),
+	})
+}
+
+// clear removes every cached solution
+func (c *imageCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// ClearImageCache removes every solution cached by WithImageCache, e.g. after the solutions were reported incorrect
+func (c *Client) ClearImageCache() {
+	if c.imageCache != nil {
+		c.imageCache.clear()
+	}
+}
@@ -0,0 +1,87 @@
+package anticaptcha
+
+import (
+	"testing"
+	"time"
+)
+
+// TestImageCacheEviction checks that a full cache evicts its least recently used solution
+func TestImageCacheEviction(t *testing.T) {
+	c := newImageCache(2, 0)
+	now := time.Now()
+
+	c.add("a", &TaskResult{TaskID: 1}, now)
+	c.add("b", &TaskResult{TaskID: 2}, now)
+	if _, ok := c.get("a", now); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	c.add("c", &TaskResult{TaskID: 3}, now)
+
+	if _, ok := c.get("b", now); ok {
+		t.Error("expected b, the least recently used, to be evicted")
+	}
+	for key, id := range map[string]float64{"a": 1, "c": 3} {
+		if result, ok := c.get(key, now); !ok || result.TaskID != id {
+			t.Errorf("expected %s to be cached with task %v", key, id)
+		}
+	}
+
+	// Adding an existing key replaces its solution without evicting another
+	c.add("a", &TaskResult{TaskID: 4}, now)
+	if result, ok := c.get("a", now); !ok || result.TaskID != 4 {
+		t.Error("expected a to be replaced")
+	}
+	if _, ok := c.get("c", now); !ok {
+		t.Error("expected c to be kept when replacing a")
+	}
+}
+
+// TestImageCacheTTL checks that solutions expire after the TTL, and never with a zero TTL
+func TestImageCacheTTL(t *testing.T) {
+	c := newImageCache(2, time.Minute)
+	now := time.Now()
+	c.add("a", &TaskResult{TaskID: 1}, now)
+
+	if _, ok := c.get("a", now.Add(time.Minute)); !ok {
+		t.Error("expected a to be cached until its TTL")
+	}
+	if _, ok := c.get("a", now.Add(time.Minute+time.Second)); ok {
+		t.Error("expected a to expire after its TTL")
+	}
+	if len(c.entries) != 0 || c.order.Len() != 0 {
+		t.Error("expected the expired solution to be removed")
+	}
+
+	forever := newImageCache(2, 0)
+	forever.add("a", &TaskResult{TaskID: 1}, now)
+	if _, ok := forever.get("a", now.Add(24*time.Hour)); !ok {
+		t.Error("expected a solution cached with a zero TTL to never expire")
+	}
+}
+
+// TestImageCacheDisabled checks that a cache with no size caches nothing
+func TestImageCacheDisabled(t *testing.T) {
+	c := newImageCache(0, 0)
+	c.add("a", &TaskResult{TaskID: 1}, time.Now())
+	if _, ok := c.get("a", time.Now()); ok {
+		t.Error("expected nothing to be cached")
+	}
+}
+
+// TestClearImageCache checks that ClearImageCache removes every cached solution
+func TestClearImageCache(t *testing.T) {
+	client := NewClientWithOptions("test-key", WithSilentLogging(), WithImageCache(2, 0))
+	now := time.Now()
+	client.imageCache.add("a", &TaskResult{TaskID: 1}, now)
+	client.imageCache.add("b", &TaskResult{TaskID: 2}, now)
+
+	client.ClearImageCache()
+	for _, key := range []string{"a", "b"} {
+		if _, ok := client.imageCache.get(key, now); ok {
+			t.Errorf("expected %s to be cleared", key)
+		}
+	}
+
+	// A client without a cache ignores the call
+	NewClientWithOptions("test-key", WithSilentLogging()).ClearImageCache()
+}
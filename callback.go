@@ -0,0 +1,92 @@
+package anticaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxCallbackBodySize is the maximum size of a callback body read by ParseCallback, well above any solution
+const maxCallbackBodySize = 1 << 20
+
+// callbackPayload is the body the API posts to the callback URL of a solved task
+type callbackPayload struct {
+	TaskResultResponse
+	TaskID float64 `json:"taskId"`
+}
+
+// CreateTaskAsync submits a task of any type like CreateTask, but asks the API to post its result to the
+// client callback URL (see WithCallbackURL) instead of waiting for it. Only the task ID is returned,
+// so no goroutine is held open polling; the posted result can be decoded with ParseCallback.
+// The typed task configurations, e.g. RecaptchaV2Proxyless, are submitted the same way with their Submit method.
+func (c *Client) CreateTaskAsync(ctx context.Context, task map[string]interface{}) (float64, error) {
+	if _, ok := task["type"].(string); !ok {
+		return 0, errors.New("task type is required")
+	}
+
+	return c.createTaskAsync(ctx, task, 0)
+}
+
+// createTaskAsync submits a task asking the API to post its result to the client callback URL
+func (c *Client) createTaskAsync(ctx context.Context, task interface{}, softID int) (float64, error) {
+	if err := validateCallbackURL(c.CallbackURL); err != nil {
+		return 0, err
+	}
+
+	return c.createTask(ctx, task, softID, c.CallbackURL)
+}
+
+// validateCallbackURL checks that a callback URL is set and can be reached by the API
+func validateCallbackURL(callbackURL string) error {
+	if callbackURL == "" {
+		return errors.New("callback URL is required")
+	}
+
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback URL %q: %w", callbackURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid callback URL %q: must be an absolute http or https URL", callbackURL)
+	}
+
+	return nil
+}
+
+// ParseCallback reads and decodes the result the API posted to a callback URL, e.g. from an http.Handler.
+// Bodies larger than 1 MiB are rejected without being read in full. It handles errors like ParseCallbackBytes.
+func ParseCallback(r *http.Request) (*TaskResult, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read callback: %w", err)
+	}
+	if len(data) > maxCallbackBodySize {
+		return nil, fmt.Errorf("callback body exceeds %d bytes", maxCallbackBodySize)
+	}
+
+	return ParseCallbackBytes(data)
+}
+
+// ParseCallbackBytes decodes the result the API posted to a callback URL.
+// Errors reported by the API, e.g. ERROR_CAPTCHA_UNSOLVABLE, are returned as *APIError,
+// along with a result carrying the task ID.
+func ParseCallbackBytes(data []byte) (*TaskResult, error) {
+	var payload callbackPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode callback: %w", err)
+	}
+
+	if apiErr := payload.apiError(); apiErr != nil {
+		return &TaskResult{TaskID: payload.TaskID}, apiErr
+	}
+
+	if payload.TaskID == 0 {
+		return nil, errors.New("failed to retrieve taskId from callback")
+	}
+
+	return newTaskResult(payload.TaskID, &payload.TaskResultResponse), nil
+}
@@ -0,0 +1,77 @@
+package anticaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSubmitSendsCallbackURL checks that a typed task is submitted with the client callback URL
+// and validated like a solve
+func TestSubmitSendsCallbackURL(t *testing.T) {
+	var body struct {
+		Task        map[string]interface{} `json:"task"`
+		CallbackURL string                 `json:"callbackUrl"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/createTask" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithCallbackURL("https://example.com/anticaptcha"),
+	)
+
+	turnstile := NewTurnstileProxyless(client)
+	if _, err := turnstile.Submit(context.Background()); err == nil {
+		t.Fatal("expected an incomplete task to be rejected before being sent")
+	}
+
+	turnstile.SetWebsiteURL("https://example.com")
+	turnstile.SetWebsiteKey("site-key")
+	taskID, err := turnstile.Submit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if taskID != 7 {
+		t.Errorf("expected task ID 7, got %v", taskID)
+	}
+	if body.CallbackURL != "https://example.com/anticaptcha" {
+		t.Errorf("expected the client callback URL to be sent, got %q", body.CallbackURL)
+	}
+	if body.Task["type"] != "TurnstileTaskProxyless" || body.Task["websiteKey"] != "site-key" {
+		t.Errorf("unexpected task sent: %v", body.Task)
+	}
+}
+
+// TestParseCallbackLimit checks that a callback body over the size limit is rejected
+func TestParseCallbackLimit(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/anticaptcha", strings.NewReader(`{"taskId":7,"solution":{"text":"`+strings.Repeat("a", maxCallbackBodySize)+`"}}`))
+	if _, err := ParseCallback(r); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected an oversized callback to be rejected, got %v", err)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/anticaptcha", strings.NewReader(`{"errorId":0,"status":"ready","taskId":7,"solution":{"text":"abc"}}`))
+	result, err := ParseCallback(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TaskID != 7 || result.Solution["text"] != "abc" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
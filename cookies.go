@@ -0,0 +1,50 @@
+package anticaptcha
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// formatCookies formats cookies as the "name1=value1; name2=value2" string expected by the API, sorted by name
+func formatCookies(cookies map[string]string) string {
+	names := make([]string, 0, len(cookies))
+	for name := range cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+cookies[name])
+	}
+
+	return strings.Join(pairs, "; ")
+}
+
+// validateCookies checks that a cookie string is a list of "name=value" pairs separated by semicolons
+func validateCookies(cookies string) error {
+	if cookies == "" {
+		return nil
+	}
+
+	for _, pair := range strings.Split(cookies, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid cookie %q: must be name=value", pair)
+		}
+		if strings.ContainsAny(name, " \t\r\n\",") {
+			return fmt.Errorf("invalid cookie name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid value for cookie %q", name)
+		}
+	}
+
+	return nil
+}
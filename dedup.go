@@ -0,0 +1,150 @@
+package anticaptcha
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// inflightGroup shares the solves of identical image tasks running at the same time
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+// inflightCall is a solve shared by every caller that submitted the same task
+type inflightCall struct {
+	ctx    *sharedContext
+	done   chan struct{}
+	result *TaskResult
+	err    error
+}
+
+// newInflightGroup creates an empty group
+func newInflightGroup() *inflightGroup {
+	return &inflightGroup{calls: make(map[string]*inflightCall)}
+}
+
+// imageKey returns the key identifying an image task: a hash of the image with every option affecting its answer
+func imageKey(task imageTask, softID int) (string, error) {
+	b, err := json.Marshal(task)
+	if err != nil {
+		return "", err
+	}
+
+	b = append(b, task.LanguagePool...)
+	sum := sha256.Sum256(append(b, strconv.Itoa(softID)...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// do runs solve once for all concurrent callers with the same key, and waits for its result or the end of ctx.
+// The solve runs detached from the cancellation of the caller that started it, so its result still reaches the
+// other callers if that caller gives up. It expires at the latest deadline of the callers waiting for it instead,
+// deadline being the one of this caller, so no caller waits for a solve bounded by a shorter deadline than its own.
+func (g *inflightGroup) do(ctx context.Context, key string, deadline time.Time, solve func(context.Context) (*TaskResult, error)) (*TaskResult, bool, error) {
+	g.mu.Lock()
+	call, shared := g.calls[key]
+	if shared {
+		call.ctx.extend(deadline)
+	} else {
+		call = &inflightCall{ctx: newSharedContext(ctx, deadline), done: make(chan struct{})}
+		g.calls[key] = call
+
+		go func() {
+			call.result, call.err = solve(call.ctx)
+			call.ctx.stop()
+
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, shared, ctx.Err()
+	case <-call.done:
+		return call.result, shared, call.err
+	}
+}
+
+// sharedContext is the context of a shared solve. It carries the values of the caller that started the solve,
+// but not its cancellation, and expires at a deadline that is extended as callers with later deadlines join.
+type sharedContext struct {
+	context.Context
+	done chan struct{}
+
+	mu       sync.Mutex
+	deadline time.Time
+	timer    *time.Timer
+	err      error
+}
+
+// newSharedContext creates the context of a solve shared from ctx, expiring at deadline
+func newSharedContext(ctx context.Context, deadline time.Time) *sharedContext {
+	s := &sharedContext{
+		// Events are only emitted to the caller's own solves, since the shared one may outlive it
+		Context:  context.WithValue(context.WithoutCancel(ctx), eventsKey{}, nil),
+		done:     make(chan struct{}),
+		deadline: deadline,
+	}
+	s.timer = time.AfterFunc(time.Until(deadline), s.expire)
+
+	return s
+}
+
+// extend pushes the deadline back to the given one if it's later and the context hasn't expired yet
+func (s *sharedContext) extend(deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil || !deadline.After(s.deadline) {
+		return
+	}
+	s.deadline = deadline
+	s.timer.Reset(time.Until(deadline))
+}
+
+// expire ends the context, unless its deadline was extended after the timer fired
+func (s *sharedContext) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil || time.Now().Before(s.deadline) {
+		return
+	}
+	s.err = context.DeadlineExceeded
+	close(s.done)
+}
+
+// stop releases the timer once the solve is over
+func (s *sharedContext) stop() {
+	s.timer.Stop()
+}
+
+// Deadline implements context.Context
+func (s *sharedContext) Deadline() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deadline, true
+}
+
+// Done implements context.Context
+func (s *sharedContext) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err implements context.Context
+func (s *sharedContext) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
@@ -0,0 +1,147 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestImageDeduplication checks that concurrent solves of the same image share a single task
+func TestImageDeduplication(t *testing.T) {
+	var creates int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			atomic.AddInt32(&creates, 1)
+			fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+		case "/getTaskResult":
+			select {
+			case <-release:
+				fmt.Fprint(w, `{"errorId":0,"status":"ready","solution":{"text":"abc"}}`)
+			default:
+				fmt.Fprint(w, `{"errorId":0,"status":"processing"}`)
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithPollInterval(10*time.Millisecond),
+		WithFirstPollDelay(-1),
+		WithImageDeduplication(),
+	)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	texts := make([]string, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			texts[i], _, errs[i] = client.SendImageContext(context.Background(), "aGVsbG8=")
+		}(i)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || texts[i] != "abc" {
+			t.Errorf("caller %d got %q, %v", i, texts[i], errs[i])
+		}
+	}
+	if n := atomic.LoadInt32(&creates); n != 1 {
+		t.Errorf("expected a single task to be created, got %d", n)
+	}
+}
+
+// TestInflightCancelledCaller checks that cancelling the caller that started a shared solve does not cancel it
+func TestInflightCancelledCaller(t *testing.T) {
+	g := newInflightGroup()
+	release := make(chan struct{})
+	solve := func(ctx context.Context) (*TaskResult, error) {
+		select {
+		case <-release:
+			return &TaskResult{TaskID: 7}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	deadline := time.Now().Add(time.Minute)
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, _, err := g.do(first, "key", deadline, solve)
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan *TaskResult)
+	go func() {
+		result, shared, err := g.do(context.Background(), "key", deadline, solve)
+		if !shared || err != nil {
+			t.Errorf("expected to share the solve, got shared %v, error %v", shared, err)
+		}
+		second <- result
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled caller to get context.Canceled, got %v", err)
+	}
+
+	close(release)
+	if result := <-second; result.id() != 7 {
+		t.Errorf("expected the other caller to get task 7, got %v", result.id())
+	}
+}
+
+// TestInflightDeadline checks that a shared solve runs until the latest deadline of its callers
+func TestInflightDeadline(t *testing.T) {
+	g := newInflightGroup()
+	solve := func(ctx context.Context) (*TaskResult, error) {
+		select {
+		case <-time.After(150 * time.Millisecond):
+			return &TaskResult{TaskID: 7}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	short, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	shortDeadline, _ := short.Deadline()
+	shortErr := make(chan error)
+	go func() {
+		_, _, err := g.do(short, "key", shortDeadline, solve)
+		shortErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	result, shared, err := g.do(context.Background(), "key", time.Now().Add(time.Second), solve)
+	if !shared || err != nil || result.id() != 7 {
+		t.Fatalf("expected the solve to outlive the shorter deadline, got task %v, shared %v, error %v", result.id(), shared, err)
+	}
+	if err := <-shortErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the caller with the shorter deadline to time out, got %v", err)
+	}
+
+	// Alone, a caller's deadline still bounds the solve
+	_, _, err = g.do(context.Background(), "alone", time.Now().Add(20*time.Millisecond), solve)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the solve to expire at the deadline of its only caller, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package anticaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// dryRun replaces the API for the solves of a client created with WithDryRun
+type dryRun struct {
+	solve  func(task map[string]interface{}) (map[string]interface{}, error)
+	lastID int64 // ID of the last simulated task
+}
+
+// WithDryRun makes every solve call fn with the task, as it would be sent in a createTask request, instead of
+// sending anything to the API: the solution it returns is the one of the task, and an error it returns fails the solve.
+// fn may block to simulate a slow solve, in which case the solve gives up with ErrSolveTimeout at the client timeout.
+// Nothing is paid for, so tests can exercise their code paths without an API key or a test server.
+// Account, report and low-level task methods such as CreateTask still send their requests.
+func WithDryRun(fn func(task map[string]interface{}) (map[string]interface{}, error)) Option {
+	return func(c *Client) {
+		if fn == nil {
+			c.dryRun = nil
+			return
+		}
+		c.dryRun = &dryRun{solve: fn}
+	}
+}
+
+// run simulates the solve of a task, returning a ready result with the solution returned by the dry run function
+func (d *dryRun) run(ctx context.Context, c *Client, task taskPayload) (*TaskResult, error) {
+	b, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task: %w", err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode task: %w", err)
+	}
+
+	taskID := float64(atomic.AddInt64(&d.lastID, 1))
+	c.logger(ctx).Info("Task created (dry run)", "task_id", taskID, "type", task.taskType())
+	emit(ctx, TaskCreated{TaskID: taskID})
+
+	type outcome struct {
+		solution map[string]interface{}
+		err      error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		solution, err := d.solve(body)
+		done <- outcome{solution, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		err := ctx.Err()
+		c.logger(ctx).Warn("Stopped waiting for task (dry run)", "task_id", taskID, "error", err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &TaskResult{TaskID: taskID}, &SolveTimeoutError{TaskID: taskID, Err: err}
+		}
+		return &TaskResult{TaskID: taskID}, fmt.Errorf("stopped waiting for task result: %w", err)
+	case out := <-done:
+		if out.err != nil {
+			return &TaskResult{TaskID: taskID}, out.err
+		}
+		if out.solution == nil {
+			return &TaskResult{TaskID: taskID}, errors.New("invalid solution format in response")
+		}
+		return newTaskResult(taskID, &TaskResultResponse{Status: StatusReady, Solution: out.solution}), nil
+	}
+}
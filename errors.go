@@ -0,0 +1,191 @@
+package anticaptcha
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is
+var (
+	ErrKeyDoesNotExist = errors.New("account authorization key not found")
+	ErrZeroBalance     = errors.New("account has zero or negative balance")
+	ErrNoSlotAvailable = errors.New("no idle workers are available at the moment")
+	ErrUnsolvable      = errors.New("captcha could not be solved")
+
+	// ErrInvalidKey matches any rejection of the API key: a key that does not exist or is malformed
+	ErrInvalidKey = errors.New("invalid API key")
+
+	ErrAccountSuspended   = errors.New("account is suspended")
+	ErrIPNotAllowed       = errors.New("request IP is not in the list of allowed IPs")
+	ErrIPBlocked          = errors.New("request IP is blocked for too many invalid requests")
+	ErrTaskNotFound       = errors.New("task not found or expired")
+	ErrTaskNotSupported   = errors.New("task type is not supported")
+	ErrInvalidImage       = errors.New("image is empty, too big or of an unsupported type")
+	ErrInvalidSiteKey     = errors.New("website key is invalid")
+	ErrInvalidDomain      = errors.New("website key is not valid for this domain")
+	ErrTokenExpired       = errors.New("captcha provider token expired")
+	ErrAllWorkersFiltered = errors.New("no worker matches the task requirements")
+	ErrTemplateNotFound   = errors.New("AntiGate template not found")
+
+	// ErrMissingField is returned, naming the field, when a required field of a task is empty
+	ErrMissingField = errors.New("missing required field")
+
+	// ErrProxy matches every error of the proxy a task was solved through, e.g. refused, timed out or banned
+	ErrProxy = errors.New("proxy error")
+)
+
+// ErrClientClosed is returned by the requests of a client after Close
+var ErrClientClosed = errors.New("client is closed")
+
+// Timeout errors, which still wrap the underlying context.DeadlineExceeded when the deadline passed
+var (
+	// ErrTimeout is returned when the solve deadline passes, whether while creating the task or polling its result
+	ErrTimeout = errors.New("timeout")
+	// ErrSolveTimeout is matched by the *SolveTimeoutError returned when a created task is not solved before
+	// the deadline or the maximum number of polls. It matches ErrTimeout.
+	ErrSolveTimeout = fmt.Errorf("task was not solved in time: %w", ErrTimeout)
+)
+
+// missingField returns the error of an empty required field
+func missingField(name string) error {
+	return fmt.Errorf("%w: %s", ErrMissingField, name)
+}
+
+// apiErrorSentinels maps API error codes to their sentinel errors
+var apiErrorSentinels = map[string][]error{
+	"ERROR_KEY_DOES_NOT_EXIST":              {ErrKeyDoesNotExist, ErrInvalidKey},
+	"ERROR_WRONG_USER_KEY":                  {ErrInvalidKey},
+	"ERROR_ZERO_BALANCE":                    {ErrZeroBalance},
+	"ERROR_NO_SLOT_AVAILABLE":               {ErrNoSlotAvailable},
+	"ERROR_CAPTCHA_UNSOLVABLE":              {ErrUnsolvable},
+	"ERROR_ACCOUNT_SUSPENDED":               {ErrAccountSuspended},
+	"ERROR_IP_NOT_ALLOWED":                  {ErrIPNotAllowed},
+	"ERROR_IP_BLOCKED":                      {ErrIPBlocked},
+	"ERROR_NO_SUCH_CAPCHA_ID":               {ErrTaskNotFound},
+	"ERROR_TASK_NOT_SUPPORTED":              {ErrTaskNotSupported},
+	"ERROR_ZERO_CAPTCHA_FILESIZE":           {ErrInvalidImage},
+	"ERROR_TOO_BIG_CAPTCHA_FILESIZE":        {ErrInvalidImage},
+	"ERROR_IMAGE_TYPE_NOT_SUPPORTED":        {ErrInvalidImage},
+	"ERROR_RECAPTCHA_INVALID_SITEKEY":       {ErrInvalidSiteKey},
+	"ERROR_RECAPTCHA_INVALID_DOMAIN":        {ErrInvalidDomain},
+	"ERROR_TOKEN_EXPIRED":                   {ErrTokenExpired},
+	"ERROR_ALL_WORKERS_FILTERED":            {ErrAllWorkersFiltered},
+	"ERROR_TEMPLATE_NOT_FOUND":              {ErrTemplateNotFound},
+	"ERROR_PROXY_CONNECT_REFUSED":           {ErrProxy},
+	"ERROR_PROXY_CONNECT_TIMEOUT":           {ErrProxy},
+	"ERROR_PROXY_READ_TIMEOUT":              {ErrProxy},
+	"ERROR_PROXY_BANNED":                    {ErrProxy},
+	"ERROR_PROXY_TRANSPARENT":               {ErrProxy},
+	"ERROR_PROXY_HAS_NO_IMAGE_SUPPORT":      {ErrProxy},
+	"ERROR_PROXY_INCOMPATIBLE_HTTP_VERSION": {ErrProxy},
+	"ERROR_PROXY_NOT_AUTHORISED":            {ErrProxy},
+}
+
+// APIError represents an error reported by the AntiCaptcha API through a non-zero errorId
+type APIError struct {
+	ErrorID          int
+	ErrorCode        string
+	ErrorDescription string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	// The API may omit the code or description, so fall back to whatever is available
+	switch {
+	case e.ErrorCode == "" && e.ErrorDescription == "":
+		return fmt.Sprintf("api error %d", e.ErrorID)
+	case e.ErrorCode == "":
+		return fmt.Sprintf("api error %d: %s", e.ErrorID, e.ErrorDescription)
+	case e.ErrorDescription == "":
+		return fmt.Sprintf("api error %d (%s)", e.ErrorID, e.ErrorCode)
+	}
+
+	return fmt.Sprintf("api error %d (%s): %s", e.ErrorID, e.ErrorCode, e.ErrorDescription)
+}
+
+// Is reports whether the error matches the given sentinel error, e.g. errors.Is(err, ErrZeroBalance)
+func (e *APIError) Is(target error) bool {
+	for _, sentinel := range apiErrorSentinels[e.ErrorCode] {
+		if sentinel == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SolveTimeoutError is returned when a created task is not solved in time, with what was known of the task,
+// so it can be resumed later with WaitForResult, even from another client, or reported.
+// It matches ErrSolveTimeout and ErrTimeout, and wraps the context error when the deadline passed.
+type SolveTimeoutError struct {
+	TaskID     float64
+	LastStatus TaskStatus // Status of the last result check, e.g. StatusProcessing; empty if the result was never checked
+	Attempts   int        // Number of result checks made
+	Err        error      // Context error when the deadline passed; nil when the maximum number of checks was reached
+}
+
+// Error implements the error interface
+func (e *SolveTimeoutError) Error() string {
+	msg := fmt.Sprintf("%s: task %.0f", ErrSolveTimeout, e.TaskID)
+	if e.LastStatus != "" {
+		msg += fmt.Sprintf(" still %s", e.LastStatus)
+	}
+	msg += fmt.Sprintf(" after %d checks", e.Attempts)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap returns ErrSolveTimeout and the context error, if any
+func (e *SolveTimeoutError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrSolveTimeout}
+	}
+
+	return []error{ErrSolveTimeout, e.Err}
+}
+
+// HTTPError represents a non-2xx HTTP response from the API
+type HTTPError struct {
+	StatusCode int
+	Body       string        // Start of the response body, truncated to a few hundred bytes
+	RetryAfter time.Duration // Delay requested by the Retry-After header, e.g. on 429 responses; zero if absent
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("non-2xx status code: %d", e.StatusCode)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+
+	return msg
+}
+
+// rejected reports whether the server refused the request without processing it: a 429, or a 503 with Retry-After
+func (e *HTTPError) rejected() bool {
+	return e.StatusCode == http.StatusTooManyRequests || (e.StatusCode == http.StatusServiceUnavailable && e.RetryAfter > 0)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
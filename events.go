@@ -0,0 +1,75 @@
+package anticaptcha
+
+import "context"
+
+// SolveEvent is a status transition of a solve, sent by SolveWithEvents.
+// It is one of TaskCreated, Polling, Solved or Failed.
+type SolveEvent interface {
+	solveEvent()
+}
+
+// TaskCreated is sent once the task was created
+type TaskCreated struct {
+	TaskID float64
+}
+
+// Polling is sent after each check of a task result that is not ready yet
+type Polling struct {
+	TaskID  float64
+	Attempt int        // Number of the check, starting at 1
+	Status  TaskStatus // Status returned by the API, e.g. StatusProcessing
+}
+
+// Solved is the last event of a successful solve
+type Solved struct {
+	Solution Solution
+}
+
+// Failed is the last event of a failed solve
+type Failed struct {
+	TaskID float64 // Zero if the task was not created
+	Err    error
+}
+
+// solveEvent marks the types that are solve events
+func (TaskCreated) solveEvent() {}
+func (Polling) solveEvent()     {}
+func (Solved) solveEvent()      {}
+func (Failed) solveEvent()      {}
+
+// eventsKey is the context key of the function solve events are emitted to
+type eventsKey struct{}
+
+// emit sends a solve event to the function carried by the context, if any
+func emit(ctx context.Context, event SolveEvent) {
+	if fn, ok := ctx.Value(eventsKey{}).(func(SolveEvent)); ok {
+		fn(event)
+	}
+}
+
+// SolveWithEvents solves in the background and returns a channel of its status transitions:
+// TaskCreated, a Polling event per check, and finally Solved or Failed, after which the channel is closed.
+// The channel must be read until it is closed, or ctx cancelled, so the background solve can finish.
+func SolveWithEvents(ctx context.Context, solver Solver) <-chan SolveEvent {
+	events := make(chan SolveEvent, 16)
+
+	send := func(event SolveEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+
+	go func() {
+		defer close(events)
+
+		solution, err := solver.Solve(context.WithValue(ctx, eventsKey{}, send))
+		if err != nil {
+			send(Failed{TaskID: solution.TaskID, Err: err})
+			return
+		}
+		send(Solved{Solution: solution})
+	}()
+
+	return events
+}
@@ -0,0 +1,333 @@
+package anticaptcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FunCaptchaProxyless represents the configuration for a FunCaptcha (Arkose Labs) proxyless task
+type FunCaptchaProxyless struct {
+	Client                   *Client
+	WebsiteURL               string
+	WebsitePublicKey         string
+	FuncaptchaAPIJSSubdomain string
+	Data                     interface{}
+	PollInterval             time.Duration
+	SoftID                   int
+}
+
+// funCaptchaTask is the payload of a FunCaptcha task
+type funCaptchaTask struct {
+	Type                     string `json:"type"`
+	WebsiteURL               string `json:"websiteURL"`
+	WebsitePublicKey         string `json:"websitePublicKey"`
+	FuncaptchaAPIJSSubdomain string `json:"funcaptchaApiJSSubdomain,omitempty"`
+	Data                     string `json:"data,omitempty"`
+	UserAgent                string `json:"userAgent,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t funCaptchaTask) taskType() string {
+	return t.Type
+}
+
+// NewFunCaptchaProxyless creates a new FunCaptchaProxyless task configuration
+func NewFunCaptchaProxyless(client *Client) *FunCaptchaProxyless {
+	return &FunCaptchaProxyless{
+		Client: client,
+		SoftID: 0,
+	}
+}
+
+// SetWebsiteURL sets the website URL for the FunCaptcha task
+func (f *FunCaptchaProxyless) SetWebsiteURL(url string) {
+	f.WebsiteURL = url
+}
+
+// SetWebsitePublicKey sets the public key of the FunCaptcha widget
+func (f *FunCaptchaProxyless) SetWebsitePublicKey(key string) {
+	f.WebsitePublicKey = key
+}
+
+// SetFuncaptchaAPIJSSubdomain sets the custom subdomain the Arkose API script is loaded from
+func (f *FunCaptchaProxyless) SetFuncaptchaAPIJSSubdomain(subdomain string) {
+	f.FuncaptchaAPIJSSubdomain = subdomain
+}
+
+// SetData sets the additional data (blob) for the FunCaptcha task.
+// It accepts either a JSON string or a map[string]interface{} that is marshaled to JSON.
+func (f *FunCaptchaProxyless) SetData(data interface{}) {
+	f.Data = data
+}
+
+// SetSoftID sets the soft ID for the FunCaptcha task
+func (f *FunCaptchaProxyless) SetSoftID(softID int) {
+	f.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (f *FunCaptchaProxyless) SetPollInterval(interval time.Duration) {
+	f.PollInterval = interval
+}
+
+// encodeData converts the configured data into the JSON string expected by the API
+func (f *FunCaptchaProxyless) encodeData() (string, error) {
+	switch data := f.Data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return data, nil
+	case map[string]interface{}:
+		b, err := json.Marshal(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal data: %w", err)
+		}
+		return string(b), nil
+	default:
+		return "", fmt.Errorf("unsupported data type %T: must be a JSON string or map[string]interface{}", f.Data)
+	}
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (f *FunCaptchaProxyless) SolveAndReturnSolution() (string, float64, error) {
+	return f.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the token.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (f *FunCaptchaProxyless) SolveAndReturnSolutionContext(ctx context.Context) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := f.solve(ctx)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	token, err := f.token(ctx, result)
+	return token, result.TaskID, err
+}
+
+// Solve implements Solver
+func (f *FunCaptchaProxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := f.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	token, err := f.token(ctx, result)
+	return newSolution(result, token), err
+}
+
+// token extracts the FunCaptcha token from a task result
+func (f *FunCaptchaProxyless) token(ctx context.Context, result *TaskResult) (string, error) {
+	token, ok := result.Solution["token"].(string)
+	if !ok {
+		f.Client.logger(ctx).Error("token not found in solution", "task_id", result.TaskID)
+		return "", errors.New("token not found in solution")
+	}
+
+	f.Client.logger(ctx).Info("FunCaptcha solved", "task_id", result.TaskID, "token", f.Client.redact(token))
+	return token, nil
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (f *FunCaptchaProxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return f.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (f *FunCaptchaProxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := f.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Client.logger(ctx).Debug("Creating FunCaptcha proxyless task")
+
+	return f.Client.solveTask(ctx, task, f.SoftID, f.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (f *FunCaptchaProxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := f.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	f.Client.logger(ctx).Debug("Submitting FunCaptcha proxyless task")
+
+	return f.Client.createTaskAsync(ctx, task, f.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (f *FunCaptchaProxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	task, err := f.task()
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// task builds the FunCaptcha proxyless task
+func (f *FunCaptchaProxyless) task() (funCaptchaTask, error) {
+	data, err := f.encodeData()
+	if err != nil {
+		return funCaptchaTask{}, err
+	}
+
+	task := funCaptchaTask{
+		Type:                     "FunCaptchaTaskProxyless",
+		WebsiteURL:               f.WebsiteURL,
+		WebsitePublicKey:         f.WebsitePublicKey,
+		FuncaptchaAPIJSSubdomain: f.FuncaptchaAPIJSSubdomain,
+		Data:                     data,
+	}
+
+	return task, nil
+}
+
+// Validate checks that the required fields are set, without sending anything
+func (f *FunCaptchaProxyless) Validate() error {
+	if err := validateWebsiteURL(f.WebsiteURL); err != nil {
+		return err
+	}
+	if f.WebsitePublicKey == "" {
+		return missingField("websitePublicKey")
+	}
+
+	return nil
+}
+
+// FunCaptchaTask represents the configuration for a FunCaptcha (Arkose Labs) task solved through a proxy
+type FunCaptchaTask struct {
+	FunCaptchaProxyless
+	Proxy     Proxy
+	UserAgent string
+}
+
+// NewFunCaptchaTask creates a new FunCaptchaTask configuration
+func NewFunCaptchaTask(client *Client) *FunCaptchaTask {
+	return &FunCaptchaTask{
+		FunCaptchaProxyless: *NewFunCaptchaProxyless(client),
+	}
+}
+
+// SetProxy sets the proxy the worker solves the FunCaptcha through
+func (f *FunCaptchaTask) SetProxy(proxy Proxy) {
+	f.Proxy = proxy
+}
+
+// SetUserAgent sets the browser user agent the worker solves the FunCaptcha with
+func (f *FunCaptchaTask) SetUserAgent(userAgent string) {
+	f.UserAgent = userAgent
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (f *FunCaptchaTask) SolveAndReturnSolution() (string, float64, error) {
+	return f.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the token.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (f *FunCaptchaTask) SolveAndReturnSolutionContext(ctx context.Context) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := f.solve(ctx)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	token, err := f.token(ctx, result)
+	return token, result.TaskID, err
+}
+
+// Solve implements Solver
+func (f *FunCaptchaTask) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := f.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	token, err := f.token(ctx, result)
+	return newSolution(result, token), err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (f *FunCaptchaTask) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return f.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (f *FunCaptchaTask) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := f.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Client.logger(ctx).Debug("Creating FunCaptcha task")
+
+	return f.Client.solveTask(ctx, task, f.SoftID, f.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (f *FunCaptchaTask) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := f.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	f.Client.logger(ctx).Debug("Submitting FunCaptcha task")
+
+	return f.Client.createTaskAsync(ctx, task, f.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (f *FunCaptchaTask) payload(ctx context.Context) (taskPayload, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	task, err := f.FunCaptchaProxyless.task()
+	if err != nil {
+		return nil, err
+	}
+	task.Type = "FunCaptchaTask"
+	task.UserAgent = f.UserAgent
+	proxy, err := f.Client.drawProxy(ctx, f.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	task.proxyPayload = proxy
+
+	return task, nil
+}
+
+// Validate checks that the required fields, the proxy and the user agent are set, without sending anything
+func (f *FunCaptchaTask) Validate() error {
+	if err := f.FunCaptchaProxyless.Validate(); err != nil {
+		return err
+	}
+	if err := f.Client.validateProxy(f.Proxy); err != nil {
+		return err
+	}
+	if f.UserAgent == "" {
+		return missingField("userAgent")
+	}
+
+	return nil
+}
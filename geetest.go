@@ -0,0 +1,385 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GeeTestSolution holds the solution of a GeeTest task.
+// Version 3 tasks fill Challenge, Validate and Seccode; version 4 tasks fill
+// CaptchaID, LotNumber, PassToken, GenTime and CaptchaOutput.
+type GeeTestSolution struct {
+	Challenge string
+	Validate  string
+	Seccode   string
+
+	CaptchaID     string
+	LotNumber     string
+	PassToken     string
+	GenTime       string
+	CaptchaOutput string
+
+	Raw map[string]interface{} // The full solution object as received, including fields not modeled above
+}
+
+// GeeTestProxyless represents the configuration for a GeeTest v3 or v4 proxyless task
+type GeeTestProxyless struct {
+	Client                    *Client
+	WebsiteURL                string
+	Version                   int
+	GT                        string
+	Challenge                 string
+	GeetestAPIServerSubdomain string
+	CaptchaID                 string
+	InitParameters            map[string]interface{}
+	PollInterval              time.Duration
+	SoftID                    int
+}
+
+// geeTestTask is the payload of a GeeTest task
+type geeTestTask struct {
+	Type                      string                 `json:"type"`
+	WebsiteURL                string                 `json:"websiteURL"`
+	GT                        string                 `json:"gt"`
+	Challenge                 string                 `json:"challenge,omitempty"`
+	GeetestAPIServerSubdomain string                 `json:"geetestApiServerSubdomain,omitempty"`
+	Version                   int                    `json:"version,omitempty"`
+	InitParameters            map[string]interface{} `json:"initParameters,omitempty"`
+	UserAgent                 string                 `json:"userAgent,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t geeTestTask) taskType() string {
+	return t.Type
+}
+
+// NewGeeTestProxyless creates a new GeeTestProxyless task configuration (version 3 by default)
+func NewGeeTestProxyless(client *Client) *GeeTestProxyless {
+	return &GeeTestProxyless{
+		Client:         client,
+		Version:        3,
+		InitParameters: make(map[string]interface{}),
+		SoftID:         0,
+	}
+}
+
+// SetWebsiteURL sets the website URL for the GeeTest task
+func (g *GeeTestProxyless) SetWebsiteURL(url string) {
+	g.WebsiteURL = url
+}
+
+// SetVersion sets the GeeTest version, either 3 or 4
+func (g *GeeTestProxyless) SetVersion(version int) {
+	g.Version = version
+}
+
+// SetGT sets the "gt" key of a GeeTest v3 widget
+func (g *GeeTestProxyless) SetGT(gt string) {
+	g.GT = gt
+}
+
+// SetChallenge sets the "challenge" value of a GeeTest v3 widget
+func (g *GeeTestProxyless) SetChallenge(challenge string) {
+	g.Challenge = challenge
+}
+
+// SetGeetestAPIServerSubdomain sets the optional custom API server subdomain
+func (g *GeeTestProxyless) SetGeetestAPIServerSubdomain(subdomain string) {
+	g.GeetestAPIServerSubdomain = subdomain
+}
+
+// SetCaptchaID sets the "captcha_id" of a GeeTest v4 widget
+func (g *GeeTestProxyless) SetCaptchaID(captchaID string) {
+	g.CaptchaID = captchaID
+}
+
+// SetInitParameters sets the initialization parameters of a GeeTest v4 widget
+func (g *GeeTestProxyless) SetInitParameters(params map[string]interface{}) {
+	g.InitParameters = params
+}
+
+// SetSoftID sets the soft ID for the GeeTest task
+func (g *GeeTestProxyless) SetSoftID(softID int) {
+	g.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (g *GeeTestProxyless) SetPollInterval(interval time.Duration) {
+	g.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (g *GeeTestProxyless) SolveAndReturnSolution() (GeeTestSolution, float64, error) {
+	return g.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns it.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (g *GeeTestProxyless) SolveAndReturnSolutionContext(ctx context.Context) (GeeTestSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := g.solve(ctx)
+	if err != nil {
+		return GeeTestSolution{}, result.id(), err
+	}
+
+	solution, err := g.solution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// Solve implements Solver, with the validate value (v3) or the pass_token (v4) as the token
+func (g *GeeTestProxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := g.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := g.solution(ctx, result)
+	return newSolution(result, g.token(solution)), err
+}
+
+// token returns the main token of a solution: the validate value (v3) or the pass_token (v4)
+func (g *GeeTestProxyless) token(solution GeeTestSolution) string {
+	if g.Version != 3 {
+		return solution.PassToken
+	}
+
+	return solution.Validate
+}
+
+// solution extracts the GeeTest solution of the configured version from a task result
+func (g *GeeTestProxyless) solution(ctx context.Context, result *TaskResult) (GeeTestSolution, error) {
+	solution := GeeTestSolution{Raw: result.Solution}
+	if g.Version == 3 {
+		solution.Challenge, _ = result.Solution["challenge"].(string)
+		solution.Validate, _ = result.Solution["validate"].(string)
+		solution.Seccode, _ = result.Solution["seccode"].(string)
+		if solution.Validate == "" {
+			g.Client.logger(ctx).Error("validate not found in solution", "task_id", result.TaskID)
+			return GeeTestSolution{}, errors.New("validate not found in solution")
+		}
+	} else {
+		solution.CaptchaID, _ = result.Solution["captcha_id"].(string)
+		solution.LotNumber, _ = result.Solution["lot_number"].(string)
+		solution.PassToken, _ = result.Solution["pass_token"].(string)
+		solution.GenTime, _ = result.Solution["gen_time"].(string)
+		solution.CaptchaOutput, _ = result.Solution["captcha_output"].(string)
+		if solution.PassToken == "" {
+			g.Client.logger(ctx).Error("pass_token not found in solution", "task_id", result.TaskID)
+			return GeeTestSolution{}, errors.New("pass_token not found in solution")
+		}
+	}
+
+	g.Client.logger(ctx).Info("GeeTest solved", "task_id", result.TaskID, "version", g.Version)
+	return solution, nil
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (g *GeeTestProxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return g.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (g *GeeTestProxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := g.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.Client.logger(ctx).Debug("Creating GeeTest proxyless task", "version", g.Version)
+
+	return g.Client.solveTask(ctx, task, g.SoftID, g.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (g *GeeTestProxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := g.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	g.Client.logger(ctx).Debug("Submitting GeeTest proxyless task", "version", g.Version)
+
+	return g.Client.createTaskAsync(ctx, task, g.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (g *GeeTestProxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	return g.task(), nil
+}
+
+// task builds the GeeTest proxyless task of the configured version
+func (g *GeeTestProxyless) task() geeTestTask {
+	task := geeTestTask{
+		Type:                      "GeeTestTaskProxyless",
+		WebsiteURL:                g.WebsiteURL,
+		GeetestAPIServerSubdomain: g.GeetestAPIServerSubdomain,
+	}
+
+	if g.Version == 4 {
+		// The v4 captcha_id is sent in the "gt" field
+		task.GT = g.CaptchaID
+		task.Version = 4
+		task.InitParameters = g.InitParameters
+	} else {
+		task.GT = g.GT
+		task.Challenge = g.Challenge
+	}
+
+	return task
+}
+
+// Validate checks that the fields required by the GeeTest version are set, without sending anything
+func (g *GeeTestProxyless) Validate() error {
+	if err := validateWebsiteURL(g.WebsiteURL); err != nil {
+		return err
+	}
+
+	switch g.Version {
+	case 3:
+		if g.GT == "" {
+			return missingField("gt")
+		}
+		if g.Challenge == "" {
+			return missingField("challenge")
+		}
+	case 4:
+		if g.CaptchaID == "" {
+			return missingField("captchaId")
+		}
+	default:
+		return fmt.Errorf("unsupported GeeTest version %d: must be 3 or 4", g.Version)
+	}
+
+	return nil
+}
+
+// GeeTestTask represents the configuration for a GeeTest v3 or v4 task solved through a proxy
+type GeeTestTask struct {
+	GeeTestProxyless
+	Proxy     Proxy
+	UserAgent string
+}
+
+// NewGeeTestTask creates a new GeeTestTask configuration (version 3 by default)
+func NewGeeTestTask(client *Client) *GeeTestTask {
+	return &GeeTestTask{
+		GeeTestProxyless: *NewGeeTestProxyless(client),
+	}
+}
+
+// SetProxy sets the proxy the worker solves the GeeTest through
+func (g *GeeTestTask) SetProxy(proxy Proxy) {
+	g.Proxy = proxy
+}
+
+// SetUserAgent sets the browser user agent the worker solves the GeeTest with
+func (g *GeeTestTask) SetUserAgent(userAgent string) {
+	g.UserAgent = userAgent
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (g *GeeTestTask) SolveAndReturnSolution() (GeeTestSolution, float64, error) {
+	return g.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns it.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (g *GeeTestTask) SolveAndReturnSolutionContext(ctx context.Context) (GeeTestSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := g.solve(ctx)
+	if err != nil {
+		return GeeTestSolution{}, result.id(), err
+	}
+
+	solution, err := g.solution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// Solve implements Solver, with the validate value (v3) or the pass_token (v4) as the token
+func (g *GeeTestTask) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := g.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := g.solution(ctx, result)
+	return newSolution(result, g.token(solution)), err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (g *GeeTestTask) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return g.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (g *GeeTestTask) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := g.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.Client.logger(ctx).Debug("Creating GeeTest task", "version", g.Version)
+
+	return g.Client.solveTask(ctx, task, g.SoftID, g.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (g *GeeTestTask) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := g.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	g.Client.logger(ctx).Debug("Submitting GeeTest task", "version", g.Version)
+
+	return g.Client.createTaskAsync(ctx, task, g.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (g *GeeTestTask) payload(ctx context.Context) (taskPayload, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := g.GeeTestProxyless.task()
+	task.Type = "GeeTestTask"
+	task.UserAgent = g.UserAgent
+	proxy, err := g.Client.drawProxy(ctx, g.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	task.proxyPayload = proxy
+
+	return task, nil
+}
+
+// Validate checks that the fields required by the GeeTest version, the proxy and the user agent are set,
+// without sending anything
+func (g *GeeTestTask) Validate() error {
+	if err := g.GeeTestProxyless.Validate(); err != nil {
+		return err
+	}
+	if err := g.Client.validateProxy(g.Proxy); err != nil {
+		return err
+	}
+	if g.UserAgent == "" {
+		return missingField("userAgent")
+	}
+
+	return nil
+}
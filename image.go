@@ -0,0 +1,92 @@
+package anticaptcha
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// SendImageFile reads an image captcha from a file, base64 encodes it, and waits for the solution.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (c *Client) SendImageFile(ctx context.Context, path string) (string, float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open image file: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil {
+			c.logger(ctx).Warn("Error closing image file", "path", path, "error", cerr)
+		}
+	}()
+
+	return c.SendImageReader(ctx, f)
+}
+
+// SendImageReader reads an image captcha from r, base64 encodes it, and waits for the solution.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (c *Client) SendImageReader(ctx context.Context, r io.Reader) (string, float64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) == 0 {
+		return "", 0, errors.New("image is empty")
+	}
+
+	return c.SendImageContext(ctx, base64.StdEncoding.EncodeToString(data))
+}
+
+// SendImageEncoded encodes an in-memory image captcha as "png" or "jpeg" ("jpg"), base64 encodes it,
+// and waits for the solution.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (c *Client) SendImageEncoded(ctx context.Context, img image.Image, format string) (string, float64, error) {
+	if img == nil {
+		return "", 0, errors.New("image is required")
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch strings.ToLower(format) {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg", "jpg":
+		err = jpeg.Encode(&buf, img, nil)
+	default:
+		return "", 0, fmt.Errorf("unsupported image format %q: must be png or jpeg", format)
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to encode image as %s: %w", format, err)
+	}
+
+	return c.SendImageContext(ctx, base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+// normalizeImage strips a leading data URI prefix, e.g. "data:image/png;base64,", from a base64 encoded image
+// and checks that the remainder is valid base64, so malformed input fails before using an API call
+func normalizeImage(imgString string) (string, error) {
+	imgString = strings.TrimSpace(imgString)
+	if strings.HasPrefix(imgString, "data:") {
+		i := strings.Index(imgString, ";base64,")
+		if i < 0 {
+			return "", errors.New("unsupported data URI: image must be base64 encoded")
+		}
+		imgString = imgString[i+len(";base64,"):]
+	}
+
+	if imgString == "" {
+		return "", errors.New("image is empty")
+	}
+	if _, err := base64.StdEncoding.DecodeString(imgString); err != nil {
+		return "", fmt.Errorf("invalid base64 image: %w", err)
+	}
+
+	return imgString, nil
+}
@@ -0,0 +1,27 @@
+package anticaptcha
+
+import "net/http"
+
+// RoundTripperFunc sends a single HTTP request to the API, like the Do method of an *http.Client
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Interceptor wraps the sending of every API request, e.g. to add headers, log or time requests.
+// It returns a RoundTripperFunc that usually calls next, possibly after changing the request,
+// and returns its response or an error of its own.
+type Interceptor func(next RoundTripperFunc) RoundTripperFunc
+
+// send sends a request through the interceptors of the client, then its HTTP client.
+// The first interceptor registered is the outermost: it sees the request first and the response last.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	do := RoundTripperFunc(c.HTTPClient.Do)
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		do = c.Interceptors[i](do)
+	}
+
+	return do(req)
+}
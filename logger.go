@@ -0,0 +1,80 @@
+package anticaptcha
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger is the logging interface used by the client.
+// Each method takes a message followed by alternating keys and values, like log/slog,
+// so a *slog.Logger can be used as is.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// stdLogger adapts a *log.Logger to the Logger interface
+type stdLogger struct {
+	logger *log.Logger
+}
+
+// NewStdLogger adapts a *log.Logger to the Logger interface.
+// Fields are appended to the message as key=value pairs.
+func NewStdLogger(logger *log.Logger) Logger {
+	return &stdLogger{logger: logger}
+}
+
+// Debug implements Logger
+func (l *stdLogger) Debug(msg string, args ...interface{}) {
+	l.print("DEBUG", msg, args)
+}
+
+// Info implements Logger
+func (l *stdLogger) Info(msg string, args ...interface{}) {
+	l.print("INFO", msg, args)
+}
+
+// Warn implements Logger
+func (l *stdLogger) Warn(msg string, args ...interface{}) {
+	l.print("WARN", msg, args)
+}
+
+// Error implements Logger
+func (l *stdLogger) Error(msg string, args ...interface{}) {
+	l.print("ERROR", msg, args)
+}
+
+// print writes a message with its level and fields on a single line
+func (l *stdLogger) print(level, msg string, args []interface{}) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteString(" ")
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", args[i])
+		}
+	}
+
+	l.logger.Println(b.String())
+}
+
+// discardLogger is a Logger that drops every message
+type discardLogger struct{}
+
+// Debug implements Logger
+func (discardLogger) Debug(string, ...interface{}) {}
+
+// Info implements Logger
+func (discardLogger) Info(string, ...interface{}) {}
+
+// Warn implements Logger
+func (discardLogger) Warn(string, ...interface{}) {}
+
+// Error implements Logger
+func (discardLogger) Error(string, ...interface{}) {}
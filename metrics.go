@@ -0,0 +1,36 @@
+package anticaptcha
+
+import "time"
+
+// Metrics receives an observation at the end of every solve, e.g. to export Prometheus metrics.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveSolve is called with the task type (e.g. "ImageToTextTask"), the duration of the solve measured by the
+	// client, from before the task is created to the last poll of its result, the cost of the task in USD
+	// (zero unless it was solved) and the error of the solve, if any.
+	ObserveSolve(taskType string, d time.Duration, cost float64, err error)
+}
+
+// ServiceTimeMetrics is implemented by Metrics that also observe the time solved tasks spent on the service.
+// The API does not report the queue wait on its own, so the service time is the queue wait plus the solving by
+// the worker, from the createTime to the endTime of the result, with a resolution of one second. Unlike the
+// duration passed to ObserveSolve, it excludes network latency and the delay until the result is polled.
+type ServiceTimeMetrics interface {
+	Metrics
+
+	// ObserveServiceTime is called after ObserveSolve for every solved task whose result carries both times
+	ObserveServiceTime(taskType string, d time.Duration)
+}
+
+// observeSolve reports a finished solve to the client metrics, if any
+func (c *Client) observeSolve(task taskPayload, d time.Duration, result *TaskResult, err error) {
+	if c.Metrics == nil {
+		return
+	}
+
+	c.Metrics.ObserveSolve(task.taskType(), d, result.cost(err), err)
+
+	if m, ok := c.Metrics.(ServiceTimeMetrics); ok && err == nil && result != nil && !result.CreateTime.IsZero() && !result.EndTime.IsZero() {
+		m.ObserveServiceTime(task.taskType(), result.Duration())
+	}
+}
@@ -0,0 +1,62 @@
+package anticaptcha
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics is a ServiceTimeMetrics keeping its observations
+type recordingMetrics struct {
+	mu          sync.Mutex
+	solves      []time.Duration
+	serviceTime []time.Duration
+}
+
+func (m *recordingMetrics) ObserveSolve(taskType string, d time.Duration, cost float64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.solves = append(m.solves, d)
+}
+
+func (m *recordingMetrics) ObserveServiceTime(taskType string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.serviceTime = append(m.serviceTime, d)
+}
+
+// TestObserveServiceTime checks that the service time of a solved task is taken from its createTime and endTime
+func TestObserveServiceTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+		case "/getTaskResult":
+			fmt.Fprint(w, `{"errorId":0,"status":"ready","solution":{"text":"abc"},"createTime":1704067200,"endTime":1704067212}`)
+		}
+	}))
+	defer server.Close()
+
+	metrics := &recordingMetrics{}
+	client := NewClientWithOptions("test-key",
+		WithSilentLogging(),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithFirstPollDelay(-1),
+		WithMetrics(metrics),
+	)
+
+	if _, _, err := client.SendImageContext(context.Background(), "aGVsbG8="); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(metrics.solves) != 1 {
+		t.Fatalf("expected 1 solve observation, got %d", len(metrics.solves))
+	}
+	if len(metrics.serviceTime) != 1 || metrics.serviceTime[0] != 12*time.Second {
+		t.Errorf("expected a service time of 12s, got %v", metrics.serviceTime)
+	}
+}
@@ -0,0 +1,288 @@
+package anticaptcha
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests, e.g. to use a custom transport.
+// A nil client is ignored.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.HTTPClient = httpClient
+		}
+	}
+}
+
+// WithDoer sets the Doer used to send requests in place of an *http.Client, e.g. a mock in tests.
+// A nil doer is ignored.
+func WithDoer(doer Doer) Option {
+	return func(c *Client) {
+		if doer != nil {
+			c.HTTPClient = doer
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper used by the HTTP client, keeping its timeout.
+// Combined with WithBaseURL, it lets tests serve the API from an httptest.Server or a fake transport.
+// If the client was given a Doer that is not an *http.Client, it is replaced by one.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		var httpClient http.Client
+		if hc, ok := c.HTTPClient.(*http.Client); ok {
+			httpClient = *hc
+		}
+		httpClient.Transport = transport
+		c.HTTPClient = &httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests sent to the API, replacing the default
+// "anticaptcha-go/<version>". It does not change the user agent the captcha tasks are solved with.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithInterceptor registers an interceptor wrapping the sending of every API request, each retry included.
+// Interceptors run in the order they are registered: the first one sees the request first and the response last,
+// and calls the next one, the last calling the HTTP client. A nil interceptor is ignored.
+func WithInterceptor(interceptor Interceptor) Option {
+	return func(c *Client) {
+		if interceptor != nil {
+			c.Interceptors = append(c.Interceptors, interceptor)
+		}
+	}
+}
+
+// WithLogger sets the logger used by the client.
+// A nil logger falls back to the default logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			c.Logger = defaultLogger
+			return
+		}
+		c.Logger = NewStdLogger(logger)
+	}
+}
+
+// WithStructuredLogger sets the structured logger used by the client, e.g. a *slog.Logger.
+// A nil logger falls back to the default logger.
+func WithStructuredLogger(logger Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = defaultLogger
+		}
+		c.Logger = logger
+	}
+}
+
+// WithSilentLogging discards all log output of the client
+func WithSilentLogging() Option {
+	return func(c *Client) {
+		c.Logger = discardLogger{}
+	}
+}
+
+// WithRedaction sets whether the API key and solutions are masked in log output (enabled by default)
+func WithRedaction(enabled bool) Option {
+	return func(c *Client) {
+		c.Redact = enabled
+	}
+}
+
+// WithTracerProvider traces every solve, and every API request as a child span, with a tracer from the given provider.
+// A nil provider disables tracing.
+func WithTracerProvider(provider TracerProvider) Option {
+	return func(c *Client) {
+		if provider == nil {
+			c.Tracer = nil
+			return
+		}
+		c.Tracer = provider.Tracer(tracerName)
+	}
+}
+
+// WithMetrics sets the metrics hook called at the end of every solve
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.Metrics = metrics
+	}
+}
+
+// WithProgress sets a callback invoked on each poll while a task is still processing, with the task status
+// and the 1-based poll attempt. It is called synchronously from the poll loop, so it must return quickly.
+func WithProgress(progress func(status string, attempt int)) Option {
+	return func(c *Client) {
+		c.Progress = progress
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second, shared by every goroutine using it.
+// Requests over the limit wait for their turn, or until their context is done, instead of failing.
+func WithRateLimit(rps float64) Option {
+	return func(c *Client) {
+		c.RateLimiter = NewRateLimiter(rps, 1)
+	}
+}
+
+// WithImageDeduplication makes identical image captchas submitted at the same time, with the same options,
+// share a single task and its solution instead of being paid for each. The shared solve is not cancelled with
+// the context of any one caller; it runs until the latest deadline of the callers waiting for it, a caller
+// without a deadline counting for the client timeout.
+func WithImageDeduplication() Option {
+	return func(c *Client) {
+		c.inflight = newInflightGroup()
+	}
+}
+
+// WithImageCache caches the solutions of up to size image captchas for ttl (forever if zero), so submitting
+// the same image with the same options again returns the cached solution instead of paying for a new task.
+// The cache is cleared with Client.ClearImageCache.
+func WithImageCache(size int, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.imageCache = newImageCache(size, ttl)
+	}
+}
+
+// WithMinBalance makes solves fail fast with ErrZeroBalance while the account balance is below threshold,
+// instead of submitting tasks that would bounce. The balance is retrieved at most every 30 seconds.
+func WithMinBalance(threshold float64) Option {
+	return func(c *Client) {
+		c.minBalance = &balanceGuard{threshold: threshold}
+	}
+}
+
+// WithProxyProvider makes every proxied task created without a proxy of its own use a fresh proxy
+// from the provider, drawn when the task is created, e.g. NewProxyRotation to cycle through a pool.
+// A proxy set on a task with SetProxy is always used as is.
+func WithProxyProvider(provider ProxyProvider) Option {
+	return func(c *Client) {
+		c.ProxyProvider = provider
+	}
+}
+
+// WithTimeout sets the maximum duration of a solve, from task creation to the last poll.
+// A zero or negative timeout falls back to the default of 60 seconds. It is the same as WithSolveTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.Timeout = timeout
+	}
+}
+
+// WithSolveTimeout sets the maximum duration of a whole solve, from task creation to the last poll,
+// independently of the duration of each request (see WithHTTPTimeout).
+// A zero or negative timeout falls back to the default of 60 seconds.
+func WithSolveTimeout(timeout time.Duration) Option {
+	return WithTimeout(timeout)
+}
+
+// WithHTTPTimeout sets the maximum duration of a single request to the API (30 seconds by default).
+// A request that times out is retried like other transient failures, within the solve timeout,
+// except task creation, which is never retried once sent.
+// Zero removes the limit, leaving only the solve timeout and the timeout of the HTTP client, if any.
+func WithHTTPTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.HTTPTimeout = timeout
+	}
+}
+
+// WithPollInterval sets the interval between result checks while waiting for a solution: the first delay of
+// the poll backoff, and the floor it never goes below. A zero or negative interval falls back to 2 seconds.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.PollInterval = interval
+	}
+}
+
+// WithPollBackoff sets how polling slows down between checks, after the first poll delay (see WithFirstPollDelay).
+// By default it follows DefaultPollBackoff, so long solves are checked less and less often. Delays never go below
+// the poll interval, where a zero Initial starts; Backoff{} polls at the fixed poll interval.
+func WithPollBackoff(backoff Backoff) Option {
+	return func(c *Client) {
+		c.PollBackoff = &backoff
+	}
+}
+
+// WithFirstPollDelay sets the delay before the first check of a task result, with ±20% jitter, for every task type.
+// By default it depends on the task type, e.g. 1s for images and 5s for HCaptcha; a negative delay checks right away.
+func WithFirstPollDelay(delay time.Duration) Option {
+	return func(c *Client) {
+		c.FirstPollDelay = delay
+	}
+}
+
+// WithMaxPollAttempts sets how many times the result of a task is checked before giving up with ErrSolveTimeout,
+// regardless of the solve timeout. Zero removes the limit; the default is 300.
+func WithMaxPollAttempts(attempts int) Option {
+	return func(c *Client) {
+		c.MaxPollAttempts = attempts
+	}
+}
+
+// WithBaseURL sets the base URL of the API, e.g. to use a compatible provider or a test server.
+// It must be an absolute http or https URL; requests fail with an error otherwise.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = baseURL
+	}
+}
+
+// WithSoftID sets the default soft ID sent with every task that does not set its own
+func WithSoftID(softID int) Option {
+	return func(c *Client) {
+		c.SoftID = softID
+	}
+}
+
+// WithCallbackURL sets the URL the API posts results to for tasks submitted with Submit or CreateTaskAsync.
+// It must be an absolute http or https URL reachable by the API.
+func WithCallbackURL(callbackURL string) Option {
+	return func(c *Client) {
+		c.CallbackURL = callbackURL
+	}
+}
+
+// WithLanguagePool sets the pool of workers every task is solved by, e.g. "en" or "rn" for Cyrillic captchas.
+// Image captchas can override it through ImageOptions.LanguagePool.
+func WithLanguagePool(pool string) Option {
+	return func(c *Client) {
+		c.LanguagePool = pool
+	}
+}
+
+// WithMaxRetries sets how many times a request is retried after a transient failure,
+// i.e. a network error, a 5xx or a 429 response. Zero disables retries; the default is 2.
+// Task creation is paid and not idempotent, so it is only retried when the connection couldn't be established
+// or the server rejected it without processing it: a 429, or a 503 with Retry-After, whose delay is honored.
+// Other 5xx responses to task creation, e.g. a 502 from a proxy that may have forwarded it, are not retried.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		c.MaxRetries = maxRetries
+	}
+}
+
+// WithRetryBackoff sets the backoff between retries of a failed request (DefaultRetryBackoff by default).
+// A zero Initial starts at the 500 milliseconds of DefaultRetryBackoff.
+func WithRetryBackoff(backoff Backoff) Option {
+	return func(c *Client) {
+		c.RetryBackoff = &backoff
+	}
+}
+
+// WithNoSlotRetry sets how task creation behaves when the API reports ERROR_NO_SLOT_AVAILABLE:
+// it is retried every delay until maxWait has elapsed (5s and 30s by default). A zero maxWait disables the retry.
+func WithNoSlotRetry(delay, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.NoSlotRetryDelay = delay
+		c.NoSlotMaxWait = maxWait
+	}
+}
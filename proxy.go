@@ -0,0 +1,112 @@
+package anticaptcha
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Proxy represents the proxy a worker uses to solve a task, so the solution is bound to the same IP as the browser
+type Proxy struct {
+	Type     string // "http", "socks4" or "socks5"
+	Address  string // IP address or hostname of the proxy
+	Port     int
+	Login    string // Optional
+	Password string // Optional
+}
+
+// validate checks that the proxy is usable by the API
+func (p Proxy) validate() error {
+	switch p.Type {
+	case "http", "socks4", "socks5":
+	case "":
+		return missingField("proxyType")
+	default:
+		return fmt.Errorf("unsupported proxy type %q: must be http, socks4 or socks5", p.Type)
+	}
+
+	if p.Address == "" {
+		return missingField("proxyAddress")
+	}
+	if p.Port <= 0 || p.Port > 65535 {
+		return fmt.Errorf("invalid proxy port %d", p.Port)
+	}
+
+	return nil
+}
+
+// proxyPayload holds the proxy fields of a task
+type proxyPayload struct {
+	ProxyType     string `json:"proxyType"`
+	ProxyAddress  string `json:"proxyAddress"`
+	ProxyPort     int    `json:"proxyPort"`
+	ProxyLogin    string `json:"proxyLogin,omitempty"`
+	ProxyPassword string `json:"proxyPassword,omitempty"`
+}
+
+// payload builds the proxy fields of a task
+func (p Proxy) payload() *proxyPayload {
+	payload := &proxyPayload{
+		ProxyType:    p.Type,
+		ProxyAddress: p.Address,
+		ProxyPort:    p.Port,
+	}
+	if p.Login != "" {
+		payload.ProxyLogin = p.Login
+		payload.ProxyPassword = p.Password
+	}
+
+	return payload
+}
+
+// ProxyProvider supplies the proxies of proxied tasks, e.g. to rotate through a pool and avoid per-IP rate limits.
+// Next is called once per proxied task without a proxy of its own, when the task is created,
+// possibly from concurrent solves, so implementations must be safe for concurrent use.
+type ProxyProvider interface {
+	Next() Proxy
+}
+
+// ProxyRotation is a ProxyProvider cycling through a fixed list of proxies
+type ProxyRotation struct {
+	proxies []Proxy
+	next    uint64
+}
+
+// NewProxyRotation creates a ProxyProvider returning the given proxies in turn
+func NewProxyRotation(proxies ...Proxy) *ProxyRotation {
+	return &ProxyRotation{proxies: proxies}
+}
+
+// Next implements ProxyProvider. It returns the zero Proxy, rejected when the task is created, if the list is empty.
+func (r *ProxyRotation) Next() Proxy {
+	if len(r.proxies) == 0 {
+		return Proxy{}
+	}
+
+	i := atomic.AddUint64(&r.next, 1) - 1
+	return r.proxies[i%uint64(len(r.proxies))]
+}
+
+// validateProxy checks the proxy set on a task. An unset proxy is valid when the client has a
+// proxy provider, since one is drawn when the task is created.
+func (c *Client) validateProxy(p Proxy) error {
+	if p == (Proxy{}) && c.ProxyProvider != nil {
+		return nil
+	}
+
+	return p.validate()
+}
+
+// drawProxy returns the proxy fields of a task: those of the proxy set on the task, or of a fresh proxy
+// from the client proxy provider if the task has none
+func (c *Client) drawProxy(ctx context.Context, p Proxy) (*proxyPayload, error) {
+	if p == (Proxy{}) && c.ProxyProvider != nil {
+		p = c.ProxyProvider.Next()
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("invalid proxy from provider: %w", err)
+		}
+		c.logger(ctx).Debug("Drew proxy from provider", "proxy_address", p.Address, "proxy_port", p.Port)
+	}
+
+	return p.payload(), nil
+}
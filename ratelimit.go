@@ -0,0 +1,73 @@
+package anticaptcha
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of API requests.
+// It is safe for concurrent use, so every request sent by a client, from any goroutine, shares one budget.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // Tokens added per second
+	burst  float64   // Maximum number of tokens
+	tokens float64   // Available tokens, negative when waiters have reserved future tokens
+	last   time.Time // Time the tokens were last updated
+}
+
+// NewRateLimiter creates a rate limiter allowing rps requests per second, with bursts of up to burst requests.
+// A burst below 1 is raised to 1.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent, or until the context is done.
+// A limiter with a zero or negative rate never blocks.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, delay); err != nil {
+		// Give the reserved token back so the waiters behind do not wait for it
+		l.mu.Lock()
+		l.tokens = min(l.tokens+1, l.burst)
+		l.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// reserve takes a token and returns how long to wait until it is available
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed.Seconds()*l.rate, l.burst)
+		l.last = now
+	}
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
@@ -0,0 +1,111 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRateLimiterBurst checks that a full bucket lets burst requests through, then spaces the next ones by 1/rps
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(10, 3)
+	now := l.last
+
+	for i := 0; i < 3; i++ {
+		if delay := l.reserve(now); delay != 0 {
+			t.Fatalf("request %d of the burst delayed by %s", i+1, delay)
+		}
+	}
+	if delay := l.reserve(now); delay != 100*time.Millisecond {
+		t.Errorf("expected the request after the burst to wait 100ms, got %s", delay)
+	}
+	if delay := l.reserve(now); delay != 200*time.Millisecond {
+		t.Errorf("expected the next request to wait 200ms, got %s", delay)
+	}
+}
+
+// TestRateLimiterRefill checks that tokens are added at the configured rate, up to the burst
+func TestRateLimiterRefill(t *testing.T) {
+	l := NewRateLimiter(10, 2)
+	now := l.last
+	l.reserve(now)
+	l.reserve(now)
+
+	now = now.Add(100 * time.Millisecond)
+	if delay := l.reserve(now); delay != 0 {
+		t.Errorf("expected a token to be refilled after 100ms, got a delay of %s", delay)
+	}
+
+	// An idle limiter never holds more than burst tokens
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if delay := l.reserve(now); delay != 0 {
+			t.Fatalf("request %d after idling delayed by %s", i+1, delay)
+		}
+	}
+	if delay := l.reserve(now); delay <= 0 {
+		t.Error("expected the bucket to be capped at the burst")
+	}
+}
+
+// TestRateLimiterCancel checks that a cancelled wait returns promptly and gives its token back
+func TestRateLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected an error matching context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("cancelled wait returned after %s", elapsed)
+	}
+
+	// The token reserved by the cancelled wait was given back, so the next one waits for a single token
+	if delay := l.reserve(time.Now()); delay > time.Second {
+		t.Errorf("expected the next request to wait at most 1s, got %s", delay)
+	}
+}
+
+// TestRateLimiterConcurrent checks that concurrent waiters share one budget
+func TestRateLimiterConcurrent(t *testing.T) {
+	const rps, burst, waiters = 200, 5, 25
+	l := NewRateLimiter(rps, burst)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Beyond the burst, the waiters are spaced by 1/rps: 20 tokens at 200 per second take 100ms
+	if elapsed, min := time.Since(start), (waiters-burst)*time.Second/rps; elapsed < min-10*time.Millisecond {
+		t.Errorf("%d waiters passed in %s, expected at least %s", waiters, elapsed, min)
+	}
+}
+
+// TestRateLimiterDisabled checks that a nil limiter or one with no rate never blocks
+func TestRateLimiterDisabled(t *testing.T) {
+	var nilLimiter *RateLimiter
+	for _, l := range []*RateLimiter{nilLimiter, NewRateLimiter(0, 1)} {
+		for i := 0; i < 10; i++ {
+			if err := l.Wait(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+}
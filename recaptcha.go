@@ -0,0 +1,482 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RecaptchaV2Proxyless represents the configuration for a reCAPTCHA v2 proxyless task
+type RecaptchaV2Proxyless struct {
+	Client              *Client
+	WebsiteURL          string
+	WebsiteKey          string
+	IsInvisible         bool
+	RecaptchaDataSValue string
+	APIDomain           string
+	PollInterval        time.Duration
+	SoftID              int
+}
+
+// NewRecaptchaV2Proxyless creates a new RecaptchaV2Proxyless task configuration
+func NewRecaptchaV2Proxyless(client *Client) *RecaptchaV2Proxyless {
+	return &RecaptchaV2Proxyless{
+		Client:      client,
+		IsInvisible: false,
+		SoftID:      0,
+	}
+}
+
+// SetWebsiteURL sets the website URL for the reCAPTCHA task
+func (r *RecaptchaV2Proxyless) SetWebsiteURL(url string) {
+	r.WebsiteURL = url
+}
+
+// SetWebsiteKey sets the website key for the reCAPTCHA task
+func (r *RecaptchaV2Proxyless) SetWebsiteKey(key string) {
+	r.WebsiteKey = key
+}
+
+// SetIsInvisible sets whether the reCAPTCHA is invisible
+func (r *RecaptchaV2Proxyless) SetIsInvisible(invisible bool) {
+	r.IsInvisible = invisible
+}
+
+// SetRecaptchaDataSValue sets the "data-s" value required by some sites (e.g. Google services)
+func (r *RecaptchaV2Proxyless) SetRecaptchaDataSValue(value string) {
+	r.RecaptchaDataSValue = value
+}
+
+// SetAPIDomain sets the domain the reCAPTCHA script is loaded from, e.g. "www.recaptcha.net"
+func (r *RecaptchaV2Proxyless) SetAPIDomain(domain string) {
+	r.APIDomain = domain
+}
+
+// SetSoftID sets the soft ID for the reCAPTCHA task
+func (r *RecaptchaV2Proxyless) SetSoftID(softID int) {
+	r.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (r *RecaptchaV2Proxyless) SetPollInterval(interval time.Duration) {
+	r.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (r *RecaptchaV2Proxyless) SolveAndReturnSolution() (string, float64, error) {
+	return r.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the gRecaptchaResponse token.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (r *RecaptchaV2Proxyless) SolveAndReturnSolutionContext(ctx context.Context) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	gResponse, err := r.Client.recaptchaResponse(ctx, result, "reCAPTCHA v2")
+	return gResponse, result.TaskID, err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (r *RecaptchaV2Proxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return r.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (r *RecaptchaV2Proxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := r.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Client.logger(ctx).Debug("Creating reCAPTCHA v2 proxyless task")
+
+	return r.Client.solveTask(ctx, task, r.SoftID, r.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (r *RecaptchaV2Proxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := r.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.Client.logger(ctx).Debug("Submitting reCAPTCHA v2 proxyless task")
+
+	return r.Client.createTaskAsync(ctx, task, r.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (r *RecaptchaV2Proxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r.task(), nil
+}
+
+// Validate checks that the required fields are set, without sending anything
+func (r *RecaptchaV2Proxyless) Validate() error {
+	if err := validateWebsiteURL(r.WebsiteURL); err != nil {
+		return err
+	}
+	if r.WebsiteKey == "" {
+		return missingField("websiteKey")
+	}
+
+	return nil
+}
+
+// Solve implements Solver, with the gRecaptchaResponse as the token
+func (r *RecaptchaV2Proxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	return r.Client.recaptchaSolve(ctx, result, err)
+}
+
+// recaptchaV2Task is the payload of a reCAPTCHA v2 task
+type recaptchaV2Task struct {
+	Type                string `json:"type"`
+	WebsiteURL          string `json:"websiteURL"`
+	WebsiteKey          string `json:"websiteKey"`
+	IsInvisible         bool   `json:"isInvisible"`
+	RecaptchaDataSValue string `json:"recaptchaDataSValue,omitempty"`
+	APIDomain           string `json:"apiDomain,omitempty"`
+	UserAgent           string `json:"userAgent,omitempty"`
+	Cookies             string `json:"cookies,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t recaptchaV2Task) taskType() string {
+	return t.Type
+}
+
+// task builds the reCAPTCHA v2 proxyless task
+func (r *RecaptchaV2Proxyless) task() recaptchaV2Task {
+	return recaptchaV2Task{
+		Type:                "RecaptchaV2TaskProxyless",
+		WebsiteURL:          r.WebsiteURL,
+		WebsiteKey:          r.WebsiteKey,
+		IsInvisible:         r.IsInvisible,
+		RecaptchaDataSValue: r.RecaptchaDataSValue,
+		APIDomain:           r.APIDomain,
+	}
+}
+
+// RecaptchaV2Task represents the configuration for a reCAPTCHA v2 task solved through a proxy
+type RecaptchaV2Task struct {
+	RecaptchaV2Proxyless
+	Proxy     Proxy
+	UserAgent string
+	Cookies   string // "name1=value1; name2=value2"
+}
+
+// NewRecaptchaV2Task creates a new RecaptchaV2Task configuration
+func NewRecaptchaV2Task(client *Client) *RecaptchaV2Task {
+	return &RecaptchaV2Task{
+		RecaptchaV2Proxyless: *NewRecaptchaV2Proxyless(client),
+	}
+}
+
+// SetProxy sets the proxy the worker solves the reCAPTCHA through
+func (r *RecaptchaV2Task) SetProxy(proxy Proxy) {
+	r.Proxy = proxy
+}
+
+// SetUserAgent sets the browser user agent the worker solves the reCAPTCHA with
+func (r *RecaptchaV2Task) SetUserAgent(userAgent string) {
+	r.UserAgent = userAgent
+}
+
+// SetCookies sets the cookies the worker solves the reCAPTCHA with, e.g. the session cookie of the website
+func (r *RecaptchaV2Task) SetCookies(cookies map[string]string) {
+	r.Cookies = formatCookies(cookies)
+}
+
+// SetCookieString sets the cookies the worker solves the reCAPTCHA with as a raw "name1=value1; name2=value2" string
+func (r *RecaptchaV2Task) SetCookieString(cookies string) {
+	r.Cookies = cookies
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (r *RecaptchaV2Task) SolveAndReturnSolution() (string, float64, error) {
+	return r.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the gRecaptchaResponse token.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (r *RecaptchaV2Task) SolveAndReturnSolutionContext(ctx context.Context) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	gResponse, err := r.Client.recaptchaResponse(ctx, result, "reCAPTCHA v2")
+	return gResponse, result.TaskID, err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (r *RecaptchaV2Task) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return r.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (r *RecaptchaV2Task) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := r.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Client.logger(ctx).Debug("Creating reCAPTCHA v2 task")
+
+	return r.Client.solveTask(ctx, task, r.SoftID, r.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (r *RecaptchaV2Task) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := r.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.Client.logger(ctx).Debug("Submitting reCAPTCHA v2 task")
+
+	return r.Client.createTaskAsync(ctx, task, r.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (r *RecaptchaV2Task) payload(ctx context.Context) (taskPayload, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := r.RecaptchaV2Proxyless.task()
+	task.Type = "RecaptchaV2Task"
+	task.UserAgent = r.UserAgent
+	task.Cookies = r.Cookies
+	proxy, err := r.Client.drawProxy(ctx, r.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	task.proxyPayload = proxy
+
+	return task, nil
+}
+
+// Validate checks that the required fields, the proxy and the user agent are set, without sending anything
+func (r *RecaptchaV2Task) Validate() error {
+	if err := r.RecaptchaV2Proxyless.Validate(); err != nil {
+		return err
+	}
+	if err := r.Client.validateProxy(r.Proxy); err != nil {
+		return err
+	}
+	if r.UserAgent == "" {
+		return missingField("userAgent")
+	}
+
+	return validateCookies(r.Cookies)
+}
+
+// Solve implements Solver, with the gRecaptchaResponse as the token
+func (r *RecaptchaV2Task) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	return r.Client.recaptchaSolve(ctx, result, err)
+}
+
+// recaptchaSolve builds the Solver solution of a reCAPTCHA task result
+func (c *Client) recaptchaSolve(ctx context.Context, result *TaskResult, err error) (Solution, error) {
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	gResponse, err := c.recaptchaResponse(ctx, result, "reCAPTCHA")
+	return newSolution(result, gResponse), err
+}
+
+// recaptchaResponse extracts the gRecaptchaResponse token from a task result
+func (c *Client) recaptchaResponse(ctx context.Context, result *TaskResult, label string) (string, error) {
+	gResponse, ok := result.Solution["gRecaptchaResponse"].(string)
+	if !ok {
+		c.logger(ctx).Error("gRecaptchaResponse not found in solution", "task_id", result.TaskID)
+		return "", errors.New("gRecaptchaResponse not found in solution")
+	}
+
+	c.logger(ctx).Info(label+" solved", "task_id", result.TaskID, "token", c.redact(gResponse))
+	return gResponse, nil
+}
+
+// RecaptchaV3Proxyless represents the configuration for a reCAPTCHA v3 proxyless task
+type RecaptchaV3Proxyless struct {
+	Client       *Client
+	WebsiteURL   string
+	WebsiteKey   string
+	MinScore     float64
+	PageAction   string
+	IsEnterprise bool
+	PollInterval time.Duration
+	SoftID       int
+}
+
+// recaptchaV3Task is the payload of a reCAPTCHA v3 task
+type recaptchaV3Task struct {
+	Type         string  `json:"type"`
+	WebsiteURL   string  `json:"websiteURL"`
+	WebsiteKey   string  `json:"websiteKey"`
+	MinScore     float64 `json:"minScore"`
+	PageAction   string  `json:"pageAction,omitempty"`
+	IsEnterprise bool    `json:"isEnterprise"`
+}
+
+// taskType implements taskPayload
+func (t recaptchaV3Task) taskType() string {
+	return t.Type
+}
+
+// NewRecaptchaV3Proxyless creates a new RecaptchaV3Proxyless task configuration
+func NewRecaptchaV3Proxyless(client *Client) *RecaptchaV3Proxyless {
+	return &RecaptchaV3Proxyless{
+		Client:       client,
+		MinScore:     0.3,
+		IsEnterprise: false,
+		SoftID:       0,
+	}
+}
+
+// SetWebsiteURL sets the website URL for the reCAPTCHA task
+func (r *RecaptchaV3Proxyless) SetWebsiteURL(url string) {
+	r.WebsiteURL = url
+}
+
+// SetWebsiteKey sets the website key for the reCAPTCHA task
+func (r *RecaptchaV3Proxyless) SetWebsiteKey(key string) {
+	r.WebsiteKey = key
+}
+
+// SetMinScore sets the minimum score required for the token (0.3, 0.7 or 0.9)
+func (r *RecaptchaV3Proxyless) SetMinScore(score float64) {
+	r.MinScore = score
+}
+
+// SetPageAction sets the action name passed to grecaptcha.execute on the target website
+func (r *RecaptchaV3Proxyless) SetPageAction(action string) {
+	r.PageAction = action
+}
+
+// SetIsEnterprise sets whether the reCAPTCHA is enterprise
+func (r *RecaptchaV3Proxyless) SetIsEnterprise(enterprise bool) {
+	r.IsEnterprise = enterprise
+}
+
+// SetSoftID sets the soft ID for the reCAPTCHA task
+func (r *RecaptchaV3Proxyless) SetSoftID(softID int) {
+	r.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (r *RecaptchaV3Proxyless) SetPollInterval(interval time.Duration) {
+	r.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (r *RecaptchaV3Proxyless) SolveAndReturnSolution() (string, float64, error) {
+	return r.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the gRecaptchaResponse token.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (r *RecaptchaV3Proxyless) SolveAndReturnSolutionContext(ctx context.Context) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	gResponse, err := r.Client.recaptchaResponse(ctx, result, "reCAPTCHA v3")
+	return gResponse, result.TaskID, err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (r *RecaptchaV3Proxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return r.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (r *RecaptchaV3Proxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := r.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Client.logger(ctx).Debug("Creating reCAPTCHA v3 proxyless task")
+
+	return r.Client.solveTask(ctx, task, r.SoftID, r.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (r *RecaptchaV3Proxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := r.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.Client.logger(ctx).Debug("Submitting reCAPTCHA v3 proxyless task")
+
+	return r.Client.createTaskAsync(ctx, task, r.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (r *RecaptchaV3Proxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := recaptchaV3Task{
+		Type:         "RecaptchaV3TaskProxyless",
+		WebsiteURL:   r.WebsiteURL,
+		WebsiteKey:   r.WebsiteKey,
+		MinScore:     r.MinScore,
+		PageAction:   r.PageAction,
+		IsEnterprise: r.IsEnterprise,
+	}
+
+	return task, nil
+}
+
+// Validate checks that the required fields are set and the minimum score is supported, without sending anything
+func (r *RecaptchaV3Proxyless) Validate() error {
+	if err := validateWebsiteURL(r.WebsiteURL); err != nil {
+		return err
+	}
+	if r.WebsiteKey == "" {
+		return missingField("websiteKey")
+	}
+	if r.MinScore != 0.3 && r.MinScore != 0.7 && r.MinScore != 0.9 {
+		return fmt.Errorf("invalid minScore %v: must be one of 0.3, 0.7 or 0.9", r.MinScore)
+	}
+
+	return nil
+}
+
+// Solve implements Solver, with the gRecaptchaResponse as the token
+func (r *RecaptchaV3Proxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	return r.Client.recaptchaSolve(ctx, result, err)
+}
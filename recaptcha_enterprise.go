@@ -0,0 +1,297 @@
+package anticaptcha
+
+import (
+	"context"
+	"time"
+)
+
+// RecaptchaV2EnterpriseProxyless represents the configuration for a reCAPTCHA v2 Enterprise proxyless task
+type RecaptchaV2EnterpriseProxyless struct {
+	Client            *Client
+	WebsiteURL        string
+	WebsiteKey        string
+	IsInvisible       bool
+	EnterprisePayload map[string]interface{}
+	APIDomain         string
+	PollInterval      time.Duration
+	SoftID            int
+}
+
+// recaptchaV2EnterpriseTask is the payload of a reCAPTCHA v2 Enterprise task
+type recaptchaV2EnterpriseTask struct {
+	Type              string                 `json:"type"`
+	WebsiteURL        string                 `json:"websiteURL"`
+	WebsiteKey        string                 `json:"websiteKey"`
+	IsInvisible       bool                   `json:"isInvisible,omitempty"`
+	EnterprisePayload map[string]interface{} `json:"enterprisePayload,omitempty"`
+	APIDomain         string                 `json:"apiDomain,omitempty"`
+	UserAgent         string                 `json:"userAgent,omitempty"`
+	Cookies           string                 `json:"cookies,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t recaptchaV2EnterpriseTask) taskType() string {
+	return t.Type
+}
+
+// NewRecaptchaV2EnterpriseProxyless creates a new RecaptchaV2EnterpriseProxyless task configuration
+func NewRecaptchaV2EnterpriseProxyless(client *Client) *RecaptchaV2EnterpriseProxyless {
+	return &RecaptchaV2EnterpriseProxyless{
+		Client:            client,
+		IsInvisible:       false,
+		EnterprisePayload: make(map[string]interface{}),
+		SoftID:            0,
+	}
+}
+
+// SetWebsiteURL sets the website URL for the reCAPTCHA task
+func (r *RecaptchaV2EnterpriseProxyless) SetWebsiteURL(url string) {
+	r.WebsiteURL = url
+}
+
+// SetWebsiteKey sets the website key for the reCAPTCHA task
+func (r *RecaptchaV2EnterpriseProxyless) SetWebsiteKey(key string) {
+	r.WebsiteKey = key
+}
+
+// SetIsInvisible sets whether the reCAPTCHA is invisible
+func (r *RecaptchaV2EnterpriseProxyless) SetIsInvisible(invisible bool) {
+	r.IsInvisible = invisible
+}
+
+// SetEnterprisePayload sets the additional parameters passed to grecaptcha.enterprise.render, e.g. {"s": "..."}
+func (r *RecaptchaV2EnterpriseProxyless) SetEnterprisePayload(payload map[string]interface{}) {
+	r.EnterprisePayload = payload
+}
+
+// SetAPIDomain sets the domain the reCAPTCHA script is loaded from, e.g. "www.recaptcha.net"
+func (r *RecaptchaV2EnterpriseProxyless) SetAPIDomain(domain string) {
+	r.APIDomain = domain
+}
+
+// SetSoftID sets the soft ID for the reCAPTCHA task
+func (r *RecaptchaV2EnterpriseProxyless) SetSoftID(softID int) {
+	r.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (r *RecaptchaV2EnterpriseProxyless) SetPollInterval(interval time.Duration) {
+	r.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (r *RecaptchaV2EnterpriseProxyless) SolveAndReturnSolution() (string, float64, error) {
+	return r.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the gRecaptchaResponse token.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (r *RecaptchaV2EnterpriseProxyless) SolveAndReturnSolutionContext(ctx context.Context) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	gResponse, err := r.Client.recaptchaResponse(ctx, result, "reCAPTCHA v2 Enterprise")
+	return gResponse, result.TaskID, err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (r *RecaptchaV2EnterpriseProxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return r.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (r *RecaptchaV2EnterpriseProxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := r.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Client.logger(ctx).Debug("Creating reCAPTCHA v2 Enterprise proxyless task")
+
+	return r.Client.solveTask(ctx, task, r.SoftID, r.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (r *RecaptchaV2EnterpriseProxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := r.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.Client.logger(ctx).Debug("Submitting reCAPTCHA v2 Enterprise proxyless task")
+
+	return r.Client.createTaskAsync(ctx, task, r.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (r *RecaptchaV2EnterpriseProxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r.task(), nil
+}
+
+// task builds the reCAPTCHA v2 Enterprise proxyless task
+func (r *RecaptchaV2EnterpriseProxyless) task() recaptchaV2EnterpriseTask {
+	return recaptchaV2EnterpriseTask{
+		Type:              "RecaptchaV2EnterpriseTaskProxyless",
+		WebsiteURL:        r.WebsiteURL,
+		WebsiteKey:        r.WebsiteKey,
+		IsInvisible:       r.IsInvisible,
+		EnterprisePayload: r.EnterprisePayload,
+		APIDomain:         r.APIDomain,
+	}
+}
+
+// Validate checks that the required fields are set, without sending anything
+func (r *RecaptchaV2EnterpriseProxyless) Validate() error {
+	if err := validateWebsiteURL(r.WebsiteURL); err != nil {
+		return err
+	}
+	if r.WebsiteKey == "" {
+		return missingField("websiteKey")
+	}
+
+	return nil
+}
+
+// Solve implements Solver, with the gRecaptchaResponse as the token
+func (r *RecaptchaV2EnterpriseProxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	return r.Client.recaptchaSolve(ctx, result, err)
+}
+
+// RecaptchaV2EnterpriseTask represents the configuration for a reCAPTCHA v2 Enterprise task solved through a proxy
+type RecaptchaV2EnterpriseTask struct {
+	RecaptchaV2EnterpriseProxyless
+	Proxy     Proxy
+	UserAgent string
+	Cookies   string // "name1=value1; name2=value2"
+}
+
+// NewRecaptchaV2EnterpriseTask creates a new RecaptchaV2EnterpriseTask configuration
+func NewRecaptchaV2EnterpriseTask(client *Client) *RecaptchaV2EnterpriseTask {
+	return &RecaptchaV2EnterpriseTask{
+		RecaptchaV2EnterpriseProxyless: *NewRecaptchaV2EnterpriseProxyless(client),
+	}
+}
+
+// SetProxy sets the proxy the worker solves the reCAPTCHA through
+func (r *RecaptchaV2EnterpriseTask) SetProxy(proxy Proxy) {
+	r.Proxy = proxy
+}
+
+// SetUserAgent sets the browser user agent the worker solves the reCAPTCHA with
+func (r *RecaptchaV2EnterpriseTask) SetUserAgent(userAgent string) {
+	r.UserAgent = userAgent
+}
+
+// SetCookies sets the cookies the worker solves the reCAPTCHA with, e.g. the session cookie of the website
+func (r *RecaptchaV2EnterpriseTask) SetCookies(cookies map[string]string) {
+	r.Cookies = formatCookies(cookies)
+}
+
+// SetCookieString sets the cookies the worker solves the reCAPTCHA with as a raw "name1=value1; name2=value2" string
+func (r *RecaptchaV2EnterpriseTask) SetCookieString(cookies string) {
+	r.Cookies = cookies
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (r *RecaptchaV2EnterpriseTask) SolveAndReturnSolution() (string, float64, error) {
+	return r.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the gRecaptchaResponse token.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (r *RecaptchaV2EnterpriseTask) SolveAndReturnSolutionContext(ctx context.Context) (string, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	if err != nil {
+		return "", result.id(), err
+	}
+
+	gResponse, err := r.Client.recaptchaResponse(ctx, result, "reCAPTCHA v2 Enterprise")
+	return gResponse, result.TaskID, err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (r *RecaptchaV2EnterpriseTask) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return r.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (r *RecaptchaV2EnterpriseTask) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := r.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Client.logger(ctx).Debug("Creating reCAPTCHA v2 Enterprise task")
+
+	return r.Client.solveTask(ctx, task, r.SoftID, r.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (r *RecaptchaV2EnterpriseTask) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := r.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.Client.logger(ctx).Debug("Submitting reCAPTCHA v2 Enterprise task")
+
+	return r.Client.createTaskAsync(ctx, task, r.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (r *RecaptchaV2EnterpriseTask) payload(ctx context.Context) (taskPayload, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := r.RecaptchaV2EnterpriseProxyless.task()
+	task.Type = "RecaptchaV2EnterpriseTask"
+	task.UserAgent = r.UserAgent
+	task.Cookies = r.Cookies
+	proxy, err := r.Client.drawProxy(ctx, r.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	task.proxyPayload = proxy
+
+	return task, nil
+}
+
+// Validate checks that the required fields, the proxy and the user agent are set, without sending anything
+func (r *RecaptchaV2EnterpriseTask) Validate() error {
+	if err := r.RecaptchaV2EnterpriseProxyless.Validate(); err != nil {
+		return err
+	}
+	if err := r.Client.validateProxy(r.Proxy); err != nil {
+		return err
+	}
+	if r.UserAgent == "" {
+		return missingField("userAgent")
+	}
+
+	return validateCookies(r.Cookies)
+}
+
+// Solve implements Solver, with the gRecaptchaResponse as the token
+func (r *RecaptchaV2EnterpriseTask) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := r.solve(ctx)
+	return r.Client.recaptchaSolve(ctx, result, err)
+}
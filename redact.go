@@ -0,0 +1,42 @@
+package anticaptcha
+
+import "encoding/json"
+
+// redacted replaces sensitive values in log output
+const redacted = "[REDACTED]"
+
+// redact masks a sensitive value, such as a solution token, unless redaction is disabled
+func (c *Client) redact(value string) string {
+	if !c.Redact || value == "" {
+		return value
+	}
+
+	return redacted
+}
+
+// redactBody masks the client key and the solution fields of a JSON body unless redaction is disabled
+func (c *Client) redactBody(data []byte) string {
+	if !c.Redact {
+		return string(data)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return redacted
+	}
+	if _, ok := body["clientKey"]; ok {
+		body["clientKey"] = redacted
+	}
+	if solution, ok := body["solution"].(map[string]interface{}); ok {
+		for key := range solution {
+			solution[key] = redacted
+		}
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return redacted
+	}
+
+	return string(b)
+}
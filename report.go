@@ -0,0 +1,129 @@
+package anticaptcha
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ReportResult represents the parsed response of a report request
+type ReportResult struct {
+	Status           string // "success" when the report was accepted
+	Accepted         bool   // Whether the report was accepted by the API
+	ErrorCode        string // API error code when reporting was not allowed
+	ErrorDescription string // API error description when reporting was not allowed
+}
+
+// reportTask sends a report about the solution of a given task to the given endpoint.
+// The parsed result is returned whenever the API answered, even if the report was rejected.
+func (c *Client) reportTask(ctx context.Context, endpoint string, taskID float64) (*ReportResult, error) {
+	body := taskRequest{
+		ClientKey: c.APIKey,
+		TaskID:    taskID,
+	}
+
+	c.logger(ctx).Debug("Reporting task", "task_id", taskID, "endpoint", endpoint)
+
+	var response statusResponse
+	err := c.makeRequest(ctx, endpoint, body, &response)
+	if err != nil {
+		c.logger(ctx).Error("Failed to report task", "task_id", taskID, "error", err)
+		return nil, fmt.Errorf("failed to report task: %w", err)
+	}
+
+	result := &ReportResult{
+		Status:           response.Status,
+		ErrorCode:        response.ErrorCode,
+		ErrorDescription: response.ErrorDescription,
+	}
+
+	// Check for API errors
+	if apiErr := response.apiError(); apiErr != nil {
+		c.logger(ctx).Error("API error reporting task", "task_id", taskID, "error", apiErr)
+		return result, apiErr
+	}
+
+	if result.Status != "success" {
+		c.logger(ctx).Warn("Report was not accepted", "task_id", taskID, "status", result.Status)
+		return result, fmt.Errorf("report was not accepted: status %q", result.Status)
+	}
+
+	result.Accepted = true
+	c.logger(ctx).Info("Report accepted", "task_id", taskID)
+
+	return result, nil
+}
+
+// ReportIncorrectImage reports an incorrectly solved image captcha so the task is refunded
+func (c *Client) ReportIncorrectImage(ctx context.Context, taskID float64) error {
+	_, err := c.reportTask(ctx, "/reportIncorrectImageCaptcha", taskID)
+	return err
+}
+
+// ReportIncorrectRecaptcha reports a reCAPTCHA token that was rejected by the target website
+func (c *Client) ReportIncorrectRecaptcha(ctx context.Context, taskID float64) (*ReportResult, error) {
+	return c.reportTask(ctx, "/reportIncorrectRecaptcha", taskID)
+}
+
+// ReportCorrectRecaptcha reports a reCAPTCHA token that was accepted by the target website
+func (c *Client) ReportCorrectRecaptcha(ctx context.Context, taskID float64) (*ReportResult, error) {
+	return c.reportTask(ctx, "/reportCorrectRecaptcha", taskID)
+}
+
+// ReportIncorrectHcaptcha reports an HCaptcha token that was rejected by the target website
+func (c *Client) ReportIncorrectHcaptcha(ctx context.Context, taskID float64) error {
+	_, err := c.reportTask(ctx, "/reportIncorrectHcaptcha", taskID)
+	return err
+}
+
+// ReportKind is the type of captcha whose incorrect solutions are reported in a batch
+type ReportKind string
+
+// Report kinds accepted by ReportIncorrectBatch
+const (
+	ReportImage     ReportKind = "image"
+	ReportRecaptcha ReportKind = "recaptcha"
+	ReportHcaptcha  ReportKind = "hcaptcha"
+)
+
+// reportEndpoints maps report kinds to the endpoint reporting an incorrect solution
+var reportEndpoints = map[ReportKind]string{
+	ReportImage:     "/reportIncorrectImageCaptcha",
+	ReportRecaptcha: "/reportIncorrectRecaptcha",
+	ReportHcaptcha:  "/reportIncorrectHcaptcha",
+}
+
+// reportBatchConcurrency is the number of reports ReportIncorrectBatch sends at the same time
+const reportBatchConcurrency = 8
+
+// ReportIncorrectBatch reports the incorrect solutions of several tasks of the same kind, a few at a time,
+// e.g. after a website changed its validation. The returned errors are in the order of taskIDs,
+// nil for each accepted report.
+func (c *Client) ReportIncorrectBatch(ctx context.Context, taskIDs []float64, kind ReportKind) []error {
+	errs := make([]error, len(taskIDs))
+
+	endpoint, ok := reportEndpoints[kind]
+	if !ok {
+		for i := range errs {
+			errs[i] = fmt.Errorf("unsupported report kind %q", kind)
+		}
+		return errs
+	}
+
+	c.logger(ctx).Debug("Reporting task batch", "tasks", len(taskIDs), "kind", kind)
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, reportBatchConcurrency)
+	for i, taskID := range taskIDs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, taskID float64) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			_, errs[i] = c.reportTask(ctx, endpoint, taskID)
+		}(i, taskID)
+	}
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,75 @@
+package anticaptcha
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// requestIDHeader is the header the request ID of a solve is sent in
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key of the request ID of a solve
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context carrying the given request ID. Solves started with it use this ID
+// instead of generating one, e.g. to reuse the ID of the incoming request that triggered them.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by the context, if any.
+// Interceptors can read the ID of the solve a request belongs to from the request context.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// withRequestID returns a context carrying a request ID, generating a random one unless the context already has one
+func withRequestID(ctx context.Context) context.Context {
+	if _, ok := RequestIDFromContext(ctx); ok {
+		return ctx
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ctx
+	}
+
+	return ContextWithRequestID(ctx, hex.EncodeToString(b))
+}
+
+// logger returns the client logger, adding the request ID carried by the context, if any, to every line
+func (c *Client) logger(ctx context.Context) Logger {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return fieldLogger{logger: c.Logger, args: []interface{}{"request_id", id}}
+	}
+
+	return c.Logger
+}
+
+// fieldLogger is a Logger adding the same fields to every line
+type fieldLogger struct {
+	logger Logger
+	args   []interface{}
+}
+
+// Debug implements Logger
+func (l fieldLogger) Debug(msg string, args ...interface{}) {
+	l.logger.Debug(msg, append(args, l.args...)...)
+}
+
+// Info implements Logger
+func (l fieldLogger) Info(msg string, args ...interface{}) {
+	l.logger.Info(msg, append(args, l.args...)...)
+}
+
+// Warn implements Logger
+func (l fieldLogger) Warn(msg string, args ...interface{}) {
+	l.logger.Warn(msg, append(args, l.args...)...)
+}
+
+// Error implements Logger
+func (l fieldLogger) Error(msg string, args ...interface{}) {
+	l.logger.Error(msg, append(args, l.args...)...)
+}
@@ -0,0 +1,123 @@
+package anticaptcha
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordingLogger is a Logger keeping every line with its fields
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []map[string]interface{}
+}
+
+func (l *recordingLogger) record(msg string, args []interface{}) {
+	line := map[string]interface{}{"msg": msg}
+	for i := 0; i+1 < len(args); i += 2 {
+		line[fmt.Sprint(args[i])] = args[i+1]
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, line)
+}
+
+func (l *recordingLogger) Debug(msg string, args ...interface{}) { l.record(msg, args) }
+func (l *recordingLogger) Info(msg string, args ...interface{})  { l.record(msg, args) }
+func (l *recordingLogger) Warn(msg string, args ...interface{})  { l.record(msg, args) }
+func (l *recordingLogger) Error(msg string, args ...interface{}) { l.record(msg, args) }
+
+// TestSolveLogsCarryRequestID checks that every log line of a solve, from the creation of the task
+// to the solved token, carries the request ID of the solve
+func TestSolveLogsCarryRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/createTask":
+			fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+		case "/getTaskResult":
+			fmt.Fprint(w, `{"errorId":0,"status":"ready","solution":{"gRecaptchaResponse":"token"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := NewClientWithOptions("test-key",
+		WithStructuredLogger(logger),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithFirstPollDelay(-1),
+	)
+
+	recaptcha := NewRecaptchaV2Proxyless(client)
+	recaptcha.SetWebsiteURL("https://example.com")
+	recaptcha.SetWebsiteKey("site-key")
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	if _, _, err := recaptcha.SolveAndReturnSolutionContext(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(logger.lines) == 0 {
+		t.Fatal("expected the solve to log")
+	}
+	for _, line := range logger.lines {
+		if line["request_id"] != "req-1" {
+			t.Errorf("log line %q has request_id %v, expected req-1", line["msg"], line["request_id"])
+		}
+	}
+	if first, last := logger.lines[0]["msg"], logger.lines[len(logger.lines)-1]["msg"]; first != "Creating reCAPTCHA v2 proxyless task" || last != "reCAPTCHA v2 solved" {
+		t.Errorf("expected the solve to be logged from its creation to its token, got %q to %q", first, last)
+	}
+}
+
+// TestSolveLogsShareGeneratedRequestID checks that a solve without a request ID of its own gets a single one,
+// shared by the lines of the balance check made in the middle of the solve
+func TestSolveLogsShareGeneratedRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/getBalance":
+			fmt.Fprint(w, `{"errorId":0,"balance":10}`)
+		case "/createTask":
+			fmt.Fprint(w, `{"errorId":0,"taskId":7}`)
+		case "/getTaskResult":
+			fmt.Fprint(w, `{"errorId":0,"status":"ready","solution":{"text":"abc"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	logger := &recordingLogger{}
+	client := NewClientWithOptions("test-key",
+		WithStructuredLogger(logger),
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithFirstPollDelay(-1),
+		WithMinBalance(1),
+	)
+
+	if _, _, err := client.SendImageContext(context.Background(), "aGVsbG8="); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	id, _ := logger.lines[0]["request_id"].(string)
+	if id == "" {
+		t.Fatal("expected the solve to get a request ID")
+	}
+	balanceLogged := false
+	for _, line := range logger.lines {
+		if line["request_id"] != id {
+			t.Errorf("log line %q has request_id %v, expected %s", line["msg"], line["request_id"], id)
+		}
+		balanceLogged = balanceLogged || line["msg"] == "Retrieving account balance"
+	}
+	if !balanceLogged {
+		t.Error("expected the balance check to be logged")
+	}
+}
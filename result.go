@@ -0,0 +1,82 @@
+package anticaptcha
+
+import "time"
+
+// TaskStatus is the status of a task, as reported by getTaskResult
+type TaskStatus string
+
+// Task statuses reported by the API
+const (
+	StatusProcessing TaskStatus = "processing" // The task is waiting for a worker or being solved
+	StatusReady      TaskStatus = "ready"      // The task is solved and the result carries its solution
+)
+
+// Done reports whether the status is terminal, i.e. polling the task again will not change its result
+func (s TaskStatus) Done() bool {
+	return s == StatusReady
+}
+
+// TaskResult represents the result of a completed task, as returned by getTaskResult
+type TaskResult struct {
+	TaskID     float64
+	Status     TaskStatus
+	Solution   map[string]interface{} // Task-specific solution object
+	Cost       float64                // Cost of the task in USD
+	IP         string                 // IP address the task was created from
+	CreateTime time.Time              // Time the task was created
+	EndTime    time.Time              // Time the task was completed
+	SolveCount int                    // Number of workers who attempted the task
+}
+
+// newTaskResult converts a getTaskResult response
+func newTaskResult(taskID float64, response *TaskResultResponse) *TaskResult {
+	result := &TaskResult{
+		TaskID:     taskID,
+		Status:     response.Status,
+		Solution:   response.Solution,
+		IP:         response.IP,
+		SolveCount: response.SolveCount,
+	}
+	result.Cost, _ = response.Cost.Float64()
+
+	if response.CreateTime > 0 {
+		result.CreateTime = time.Unix(response.CreateTime, 0)
+	}
+	if response.EndTime > 0 {
+		result.EndTime = time.Unix(response.EndTime, 0)
+	}
+
+	return result
+}
+
+// Ready reports whether the task is solved and the result carries its solution
+func (r *TaskResult) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// Duration returns the time the task took to be solved
+func (r *TaskResult) Duration() time.Duration {
+	if r.CreateTime.IsZero() || r.EndTime.IsZero() {
+		return 0
+	}
+
+	return r.EndTime.Sub(r.CreateTime)
+}
+
+// cost returns the cost of a possibly nil result, or zero if the solve failed with err
+func (r *TaskResult) cost(err error) float64 {
+	if err != nil || r == nil {
+		return 0
+	}
+
+	return r.Cost
+}
+
+// id returns the task ID of a possibly nil result
+func (r *TaskResult) id() float64 {
+	if r == nil {
+		return 0
+	}
+
+	return r.TaskID
+}
@@ -0,0 +1,49 @@
+package anticaptcha
+
+import "context"
+
+// Solver is implemented by every captcha type, so heterogeneous captchas can be solved uniformly.
+// Solving never modifies the captcha configuration: the solution is only returned, so a configured captcha
+// can be solved from concurrent goroutines as long as its setters are not called meanwhile.
+// Solve returns promptly once ctx is done, with an error wrapping the context error.
+type Solver interface {
+	Solve(ctx context.Context) (Solution, error)
+}
+
+// Solution is the answer returned by a Solver
+type Solution struct {
+	TaskID float64
+	Token  string                 // The answer to submit: the image text or the captcha token
+	Fields map[string]interface{} // The full solution object, e.g. with the user agent the token was issued for
+}
+
+// newSolution builds the solution of a solved task with its main token
+func newSolution(result *TaskResult, token string) Solution {
+	return Solution{
+		TaskID: result.TaskID,
+		Token:  token,
+		Fields: result.Solution,
+	}
+}
+
+// Every captcha type implements Solver
+var (
+	_ Solver = (*ImageCaptcha)(nil)
+	_ Solver = (*HCaptchaProxyless)(nil)
+	_ Solver = (*HCaptchaTask)(nil)
+	_ Solver = (*RecaptchaV2Proxyless)(nil)
+	_ Solver = (*RecaptchaV2Task)(nil)
+	_ Solver = (*RecaptchaV2EnterpriseProxyless)(nil)
+	_ Solver = (*RecaptchaV2EnterpriseTask)(nil)
+	_ Solver = (*RecaptchaV3Proxyless)(nil)
+	_ Solver = (*TurnstileProxyless)(nil)
+	_ Solver = (*TurnstileTask)(nil)
+	_ Solver = (*FunCaptchaProxyless)(nil)
+	_ Solver = (*FunCaptchaTask)(nil)
+	_ Solver = (*GeeTestProxyless)(nil)
+	_ Solver = (*GeeTestTask)(nil)
+	_ Solver = (*AmazonWAFProxyless)(nil)
+	_ Solver = (*AmazonWAFTask)(nil)
+	_ Solver = (*AntiBotCookie)(nil)
+	_ Solver = (*AntiGate)(nil)
+)
@@ -0,0 +1,141 @@
+package anticaptcha
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyBounds are the upper bounds of the solve latency histogram buckets
+var latencyBounds = []time.Duration{
+	5 * time.Second,
+	10 * time.Second,
+	20 * time.Second,
+	30 * time.Second,
+	60 * time.Second,
+	2 * time.Minute,
+}
+
+// ClientStats is a snapshot of the solves of a client since it was created or its stats were last reset.
+// Only solves that reached the API are counted: image solutions served from the cache or shared with an
+// identical image are not.
+type ClientStats struct {
+	Attempted int64   // Solves started, including those still running
+	Succeeded int64   // Solves that returned a solution
+	Failed    int64   // Solves that returned an error
+	TotalCost float64 // Cost of the succeeded solves in USD
+	Latency   LatencySummary
+}
+
+// LatencySummary summarizes the duration of finished solves, from their start to their result
+type LatencySummary struct {
+	Count   int64 // Number of finished solves
+	Total   time.Duration
+	Min     time.Duration
+	Max     time.Duration
+	Buckets []LatencyBucket // Histogram of the durations, in increasing order of bounds
+}
+
+// LatencyBucket counts the finished solves that took longer than the previous bucket bound and at most UpperBound.
+// The last bucket has no upper bound, which is reported as zero.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      int64
+}
+
+// Mean returns the average duration of finished solves, or zero if none finished
+func (l LatencySummary) Mean() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+
+	return l.Total / time.Duration(l.Count)
+}
+
+// clientStats accumulates the stats of a client, safe for concurrent use
+type clientStats struct {
+	mu      sync.Mutex
+	current ClientStats
+}
+
+// start counts a solve that is starting
+func (s *clientStats) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.current.Attempted++
+}
+
+// finish counts a finished solve with its duration and, if it succeeded, its cost
+func (s *clientStats) finish(d time.Duration, cost float64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		s.current.Failed++
+	} else {
+		s.current.Succeeded++
+		s.current.TotalCost += cost
+	}
+
+	latency := &s.current.Latency
+	if latency.Count == 0 || d < latency.Min {
+		latency.Min = d
+	}
+	if d > latency.Max {
+		latency.Max = d
+	}
+	latency.Count++
+	latency.Total += d
+
+	if latency.Buckets == nil {
+		latency.Buckets = newLatencyBuckets()
+	}
+	for i := range latency.Buckets {
+		bound := latency.Buckets[i].UpperBound
+		if bound == 0 || d <= bound {
+			latency.Buckets[i].Count++
+			break
+		}
+	}
+}
+
+// snapshot returns a copy of the current stats, optionally resetting them
+func (s *clientStats) snapshot(reset bool) ClientStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.current
+	if stats.Latency.Buckets == nil {
+		stats.Latency.Buckets = newLatencyBuckets()
+	} else {
+		stats.Latency.Buckets = append([]LatencyBucket(nil), stats.Latency.Buckets...)
+	}
+
+	if reset {
+		s.current = ClientStats{}
+	}
+
+	return stats
+}
+
+// newLatencyBuckets returns empty histogram buckets, the last one without upper bound
+func newLatencyBuckets() []LatencyBucket {
+	buckets := make([]LatencyBucket, len(latencyBounds)+1)
+	for i, bound := range latencyBounds {
+		buckets[i].UpperBound = bound
+	}
+
+	return buckets
+}
+
+// Stats returns the cumulative counts, cost and latency of the solves of the client.
+// It is safe to call while solves are running.
+func (c *Client) Stats() ClientStats {
+	return c.stats.snapshot(false)
+}
+
+// ResetStats returns the stats of the client, like Stats, and resets them to zero in the same step,
+// so no solve is lost between the two. Solves running during the reset are only counted once they finish.
+func (c *Client) ResetStats() ClientStats {
+	return c.stats.snapshot(true)
+}
@@ -0,0 +1,54 @@
+package anticaptcha
+
+import "context"
+
+// tracerName is the instrumentation name the client requests its tracer with
+const tracerName = modulePath
+
+// TracerProvider provides the tracer used by the client.
+// It mirrors the subset of the OpenTelemetry tracing API the client needs, so the library
+// does not depend on OpenTelemetry; see the README for an adapter.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer starts spans. The returned context carries the span, so spans started from it are its children.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// noopSpan is the Span used when tracing is disabled
+type noopSpan struct{}
+
+// SetAttribute implements Span
+func (noopSpan) SetAttribute(string, interface{}) {}
+
+// RecordError implements Span
+func (noopSpan) RecordError(error) {}
+
+// End implements Span
+func (noopSpan) End() {}
+
+// startSpan starts a span with the client tracer, or a no-op span when tracing is disabled
+func (c *Client) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if c.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return c.Tracer.Start(ctx, name)
+}
+
+// endSpan records the error, if any, and ends the span
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
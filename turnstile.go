@@ -0,0 +1,307 @@
+package anticaptcha
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TurnstileSolution holds the token returned for a Turnstile task and the user agent it was issued for
+type TurnstileSolution struct {
+	Token     string
+	UserAgent string
+
+	Raw map[string]interface{} // The full solution object as received, including fields not modeled above
+}
+
+// TurnstileProxyless represents the configuration for a Cloudflare Turnstile proxyless task
+type TurnstileProxyless struct {
+	Client       *Client
+	WebsiteURL   string
+	WebsiteKey   string
+	Action       string
+	CData        string
+	PollInterval time.Duration
+	SoftID       int
+}
+
+// turnstileTask is the payload of a Turnstile task
+type turnstileTask struct {
+	Type       string `json:"type"`
+	WebsiteURL string `json:"websiteURL"`
+	WebsiteKey string `json:"websiteKey"`
+	Action     string `json:"action,omitempty"`
+	CData      string `json:"turnstileCData,omitempty"`
+	UserAgent  string `json:"userAgent,omitempty"`
+	*proxyPayload
+}
+
+// taskType implements taskPayload
+func (t turnstileTask) taskType() string {
+	return t.Type
+}
+
+// NewTurnstileProxyless creates a new TurnstileProxyless task configuration
+func NewTurnstileProxyless(client *Client) *TurnstileProxyless {
+	return &TurnstileProxyless{
+		Client: client,
+		SoftID: 0,
+	}
+}
+
+// SetWebsiteURL sets the website URL for the Turnstile task
+func (t *TurnstileProxyless) SetWebsiteURL(url string) {
+	t.WebsiteURL = url
+}
+
+// SetWebsiteKey sets the website key for the Turnstile task
+func (t *TurnstileProxyless) SetWebsiteKey(key string) {
+	t.WebsiteKey = key
+}
+
+// SetAction sets the optional action parameter of the Turnstile widget
+func (t *TurnstileProxyless) SetAction(action string) {
+	t.Action = action
+}
+
+// SetCData sets the optional cData parameter of the Turnstile widget
+func (t *TurnstileProxyless) SetCData(cData string) {
+	t.CData = cData
+}
+
+// SetSoftID sets the soft ID for the Turnstile task
+func (t *TurnstileProxyless) SetSoftID(softID int) {
+	t.SoftID = softID
+}
+
+// SetPollInterval sets the interval between checks of the task result, overriding the client poll interval and backoff
+func (t *TurnstileProxyless) SetPollInterval(interval time.Duration) {
+	t.PollInterval = interval
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (t *TurnstileProxyless) SolveAndReturnSolution() (TurnstileSolution, float64, error) {
+	return t.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the token with its user agent.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (t *TurnstileProxyless) SolveAndReturnSolutionContext(ctx context.Context) (TurnstileSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := t.solve(ctx)
+	if err != nil {
+		return TurnstileSolution{}, result.id(), err
+	}
+
+	solution, err := t.solution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// Solve implements Solver
+func (t *TurnstileProxyless) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := t.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := t.solution(ctx, result)
+	return newSolution(result, solution.Token), err
+}
+
+// solution extracts the Turnstile solution from a task result
+func (t *TurnstileProxyless) solution(ctx context.Context, result *TaskResult) (TurnstileSolution, error) {
+	token, ok := result.Solution["token"].(string)
+	if !ok {
+		t.Client.logger(ctx).Error("token not found in solution", "task_id", result.TaskID)
+		return TurnstileSolution{}, errors.New("token not found in solution")
+	}
+
+	userAgent, _ := result.Solution["userAgent"].(string)
+
+	t.Client.logger(ctx).Info("Turnstile solved", "task_id", result.TaskID, "token", t.Client.redact(token))
+	return TurnstileSolution{Token: token, UserAgent: userAgent, Raw: result.Solution}, nil
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (t *TurnstileProxyless) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return t.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (t *TurnstileProxyless) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := t.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Client.logger(ctx).Debug("Creating Turnstile proxyless task")
+
+	return t.Client.solveTask(ctx, task, t.SoftID, t.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (t *TurnstileProxyless) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := t.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	t.Client.logger(ctx).Debug("Submitting Turnstile proxyless task")
+
+	return t.Client.createTaskAsync(ctx, task, t.SoftID)
+}
+
+// payload validates the configuration and builds the task
+func (t *TurnstileProxyless) payload(_ context.Context) (taskPayload, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t.task(), nil
+}
+
+// task builds the Turnstile proxyless task
+func (t *TurnstileProxyless) task() turnstileTask {
+	return turnstileTask{
+		Type:       "TurnstileTaskProxyless",
+		WebsiteURL: t.WebsiteURL,
+		WebsiteKey: t.WebsiteKey,
+		Action:     t.Action,
+		CData:      t.CData,
+	}
+}
+
+// Validate checks that the required fields are set, without sending anything
+func (t *TurnstileProxyless) Validate() error {
+	if err := validateWebsiteURL(t.WebsiteURL); err != nil {
+		return err
+	}
+	if t.WebsiteKey == "" {
+		return missingField("websiteKey")
+	}
+
+	return nil
+}
+
+// TurnstileTask represents the configuration for a Cloudflare Turnstile task solved through a proxy
+type TurnstileTask struct {
+	TurnstileProxyless
+	Proxy     Proxy
+	UserAgent string
+}
+
+// NewTurnstileTask creates a new TurnstileTask configuration
+func NewTurnstileTask(client *Client) *TurnstileTask {
+	return &TurnstileTask{
+		TurnstileProxyless: *NewTurnstileProxyless(client),
+	}
+}
+
+// SetProxy sets the proxy the worker solves the Turnstile through
+func (t *TurnstileTask) SetProxy(proxy Proxy) {
+	t.Proxy = proxy
+}
+
+// SetUserAgent sets the browser user agent the worker solves the Turnstile with
+func (t *TurnstileTask) SetUserAgent(userAgent string) {
+	t.UserAgent = userAgent
+}
+
+// SolveAndReturnSolution is like SolveAndReturnSolutionContext but uses a background context
+func (t *TurnstileTask) SolveAndReturnSolution() (TurnstileSolution, float64, error) {
+	return t.SolveAndReturnSolutionContext(context.Background())
+}
+
+// SolveAndReturnSolutionContext creates the task, waits for the solution, and returns the token with its user agent.
+// The task ID is returned alongside the solution, and also with any error raised after the task was created.
+func (t *TurnstileTask) SolveAndReturnSolutionContext(ctx context.Context) (TurnstileSolution, float64, error) {
+	ctx = withRequestID(ctx)
+	result, err := t.solve(ctx)
+	if err != nil {
+		return TurnstileSolution{}, result.id(), err
+	}
+
+	solution, err := t.solution(ctx, result)
+	return solution, result.TaskID, err
+}
+
+// Solve implements Solver
+func (t *TurnstileTask) Solve(ctx context.Context) (Solution, error) {
+	ctx = withRequestID(ctx)
+	result, err := t.solve(ctx)
+	if err != nil {
+		return Solution{TaskID: result.id()}, err
+	}
+
+	solution, err := t.solution(ctx, result)
+	return newSolution(result, solution.Token), err
+}
+
+// SolveDetailed creates the task and waits for the full task result, including its cost and timing.
+// If the task was created, the result carries its ID even on error.
+func (t *TurnstileTask) SolveDetailed(ctx context.Context) (*TaskResult, error) {
+	return t.solve(withRequestID(ctx))
+}
+
+// solve creates the task and waits for its result, ctx already carrying the request ID of the solve
+func (t *TurnstileTask) solve(ctx context.Context) (*TaskResult, error) {
+	task, err := t.payload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Client.logger(ctx).Debug("Creating Turnstile task")
+
+	return t.Client.solveTask(ctx, task, t.SoftID, t.PollInterval)
+}
+
+// Submit creates the task without waiting for it and returns its ID. The API posts the result to the client
+// callback URL (see WithCallbackURL), where it can be decoded with ParseCallback.
+func (t *TurnstileTask) Submit(ctx context.Context) (float64, error) {
+	ctx = withRequestID(ctx)
+	task, err := t.payload(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	t.Client.logger(ctx).Debug("Submitting Turnstile task")
+
+	return t.Client.createTaskAsync(ctx, task, t.SoftID)
+}
+
+// payload validates the configuration and builds the task, drawing its proxy if needed
+func (t *TurnstileTask) payload(ctx context.Context) (taskPayload, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	task := t.TurnstileProxyless.task()
+	task.Type = "TurnstileTask"
+	task.UserAgent = t.UserAgent
+	proxy, err := t.Client.drawProxy(ctx, t.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	task.proxyPayload = proxy
+
+	return task, nil
+}
+
+// Validate checks that the required fields, the proxy and the user agent are set, without sending anything
+func (t *TurnstileTask) Validate() error {
+	if err := t.TurnstileProxyless.Validate(); err != nil {
+		return err
+	}
+	if err := t.Client.validateProxy(t.Proxy); err != nil {
+		return err
+	}
+	if t.UserAgent == "" {
+		return missingField("userAgent")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package anticaptcha
+
+import "encoding/json"
+
+// errorResponse holds the error fields common to every API response
+type errorResponse struct {
+	ErrorID          int    `json:"errorId"`
+	ErrorCode        string `json:"errorCode,omitempty"`
+	ErrorDescription string `json:"errorDescription,omitempty"`
+}
+
+// apiError returns the API error reported in the response, or nil if the response has no error
+func (r errorResponse) apiError() *APIError {
+	if r.ErrorID == 0 {
+		return nil
+	}
+
+	return &APIError{
+		ErrorID:          r.ErrorID,
+		ErrorCode:        r.ErrorCode,
+		ErrorDescription: r.ErrorDescription,
+	}
+}
+
+// CreateTaskRequest is the body of a createTask request
+type CreateTaskRequest struct {
+	ClientKey    string      `json:"clientKey"`
+	Task         interface{} `json:"task"`
+	SoftID       int         `json:"softId,omitempty"`
+	LanguagePool string      `json:"languagePool,omitempty"`
+	CallbackURL  string      `json:"callbackUrl,omitempty"`
+}
+
+// CreateTaskResponse is the body of a createTask response
+type CreateTaskResponse struct {
+	errorResponse
+	TaskID float64 `json:"taskId"`
+}
+
+// TaskResultRequest is the body of a getTaskResult request
+type TaskResultRequest struct {
+	ClientKey string  `json:"clientKey"`
+	TaskID    float64 `json:"taskId"`
+}
+
+// TaskResultResponse is the body of a getTaskResult response
+type TaskResultResponse struct {
+	errorResponse
+	Status     TaskStatus             `json:"status"`
+	Solution   map[string]interface{} `json:"solution,omitempty"`
+	Cost       json.Number            `json:"cost,omitempty"`
+	IP         string                 `json:"ip,omitempty"`
+	CreateTime int64                  `json:"createTime,omitempty"`
+	EndTime    int64                  `json:"endTime,omitempty"`
+	SolveCount int                    `json:"solveCount,omitempty"`
+}
+
+// clientKeyRequest is the body of requests that only need the account key
+type clientKeyRequest struct {
+	ClientKey string `json:"clientKey"`
+}
+
+// taskRequest is the body of requests about a single task
+type taskRequest struct {
+	ClientKey string  `json:"clientKey"`
+	TaskID    float64 `json:"taskId"`
+}
+
+// statusResponse is the body of responses that only report a status
+type statusResponse struct {
+	errorResponse
+	Status string `json:"status"`
+}
+
+// taskPayload is the task object of a createTask request
+type taskPayload interface {
+	taskType() string
+}
+
+// languagePooler is implemented by task payloads that can set their own language pool
+type languagePooler interface {
+	languagePool() string
+}
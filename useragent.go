@@ -0,0 +1,34 @@
+package anticaptcha
+
+import "runtime/debug"
+
+// modulePath is the import path of the library
+const modulePath = "github.com/DanielFillol/anticaptcha"
+
+// defaultUserAgent is the User-Agent header sent with every API request unless WithUserAgent replaces it,
+// e.g. "anticaptcha-go/v1.2.0", identifying the library to the provider
+var defaultUserAgent = "anticaptcha-go/" + moduleVersion()
+
+// moduleVersion returns the version of the library the running binary was built with,
+// or "devel" when it cannot be determined, e.g. when the library is the main module
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		// Modules replaced by a local directory have no version
+		if dep.Version != "" && dep.Version != "(devel)" {
+			return dep.Version
+		}
+	}
+
+	return "devel"
+}